@@ -2,28 +2,82 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"distributed-worker-system/pkg/config"
 	"distributed-worker-system/pkg/coordinator"
-
-	"github.com/nats-io/nats.go"
+	"distributed-worker-system/pkg/signing"
+	"distributed-worker-system/pkg/store"
 )
 
 func main() {
+	// Load configuration from the config file, environment and flags
+	cfg, err := config.LoadCoordinator(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	policy, _ := coordinator.ParseSignaturePolicy(cfg.Signatures)
+
+	// Open the local store for worker reliability stats
+	st, err := store.Open(cfg.DataPath)
+	if err != nil {
+		log.Fatalf("Failed to open data store: %v", err)
+	}
+	defer st.Close()
+
 	// Connect to NATS
-	nc, err := nats.Connect(nats.DefaultURL)
+	nc, err := cfg.NATS.Connect("oracle-coordinator")
 	if err != nil {
 		log.Fatalf("Failed to connect to NATS: %v", err)
 	}
 	defer nc.Close()
 
-	log.Printf("🔗 Connected to NATS at %s", nats.DefaultURL)
+	log.Printf("🔗 Connected to NATS at %s (auth: %s, tls: %t)", cfg.NATS.URL, cfg.NATS.AuthMode(), cfg.NATS.TLSEnabled())
 
 	// Create coordinator instance
-	coord := coordinator.NewCoordinator(nc, 8080)
+	coord := coordinator.NewCoordinator(nc, cfg.Port)
+	if cfg.ID != "" {
+		coord.SetID(cfg.ID)
+	}
+	if err := coord.UseStore(st); err != nil {
+		log.Fatalf("Failed to load coordinator state: %v", err)
+	}
+	coord.SetRequestLimits(cfg.Limits)
+	if err := coord.SetDefaultOptions(cfg.RequestOptions()); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	coord.SetSubjects(cfg.Subjects)
+	coord.SetNATSSecurity(cfg.NATS.AuthMode(), cfg.NATS.TLS.CertFile != "")
+	coord.SetRateLimit(cfg.RateLimit, cfg.RateBurst)
+	coord.SetWorkerTimeouts(cfg.SuspectAfter, cfg.EvictAfter)
+	coord.SetResultRetention(cfg.Retention)
+	coord.SetSignaturePolicy(policy)
+
+	if cfg.StateBucket != "" {
+		if err := coord.UseSharedState(cfg.StateBucket, cfg.LeaseTTL); err != nil {
+			log.Fatalf("Failed to set up shared request state: %v", err)
+		}
+	}
+
+	if cfg.TaskStream != "" {
+		if err := coord.UseTaskStream(cfg.TaskStream, cfg.TaskMaxAge); err != nil {
+			log.Fatalf("Failed to set up task stream: %v", err)
+		}
+	}
+
+	key, err := signing.LoadOrCreateKey(cfg.KeyPath)
+	if err != nil {
+		log.Fatalf("Failed to load coordinator key: %v", err)
+	}
+	coord.SetSigningKey(key)
 
 	// Start results subscription in a goroutine
 	ctx, cancel := context.WithCancel(context.Background())
@@ -35,16 +89,23 @@ func main() {
 		}
 	}()
 
+	// Start worker registration and heartbeat tracking
+	go func() {
+		if err := coord.SubscribeWorkers(ctx); err != nil {
+			log.Printf("❌ Failed to subscribe to worker registrations: %v", err)
+		}
+	}()
+
 	// Start coordinator HTTP server in a goroutine
 	go func() {
 		coord.StartHTTPServer()
 	}()
 
-	log.Printf("🎯 Coordinator started successfully!")
-	log.Printf("📡 Coordinator API available at: http://localhost:8080")
-	log.Printf("🚀 Submit requests at: POST http://localhost:8080/request")
+	log.Printf("🎯 Coordinator %s started successfully!", coord.ID())
+	log.Printf("📡 Coordinator API available at: http://localhost:%d", cfg.Port)
+	log.Printf("🚀 Submit requests at: POST http://localhost:%d/request", cfg.Port)
 	log.Printf("💡 Example request submission:")
-	log.Printf("   curl -X POST http://localhost:8080/request \\")
+	log.Printf("   curl -X POST http://localhost:%d/request \\", cfg.Port)
 	log.Printf("     -H 'Content-Type: application/json' \\")
 	log.Printf("     -d '{\"query\":\"BTC/USD\"}'")
 
@@ -54,6 +115,11 @@ func main() {
 	<-c
 
 	log.Printf("🛑 Shutting down coordinator...")
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := coord.Shutdown(shutdownCtx); err != nil {
+		log.Printf("❌ Shutdown error: %v", err)
+	}
 	cancel()
-	coord.Close()
+	log.Printf("✅ Coordinator stopped")
 }
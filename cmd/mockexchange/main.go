@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"distributed-worker-system/pkg/mockexchange"
+)
+
+func main() {
+	// Parse command line flags
+	var scenarioPath = flag.String("scenario", "config/scenarios.example.yaml", "Path to a YAML or JSON scenario file")
+	var listen = flag.String("listen", "", "Address to listen on (overrides the scenario)")
+	flag.Parse()
+
+	scenario, err := mockexchange.LoadScenario(*scenarioPath)
+	if err != nil {
+		log.Fatalf("Failed to load scenario: %v", err)
+	}
+
+	addr := scenario.Listen
+	if *listen != "" {
+		addr = *listen
+	}
+	if addr == "" {
+		addr = ":9090"
+	}
+
+	server := mockexchange.NewServer(scenario)
+
+	log.Printf("🏦 Mock exchange serving %d tickers on %s", len(scenario.Tickers), addr)
+	log.Printf("💡 Example: curl http://localhost%s/ticker/%s", addr, scenario.Tickers[0].Symbol)
+	if err := http.ListenAndServe(addr, server.Handler()); err != nil {
+		log.Fatalf("Mock exchange stopped: %v", err)
+	}
+}
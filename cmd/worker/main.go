@@ -1,47 +1,91 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"distributed-worker-system/pkg/config"
+	"distributed-worker-system/pkg/signing"
 	"distributed-worker-system/pkg/worker"
-
-	"github.com/nats-io/nats.go"
 )
 
 func main() {
-	// Parse command line flags
-	var port = flag.Int("port", 8081, "Port for the worker (used for identification)")
-	flag.Parse()
+	// Load configuration from the config file, environment and flags
+	cfg, err := config.LoadWorker(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	key, err := signing.LoadOrCreateKey(cfg.KeyPath)
+	if err != nil {
+		log.Fatalf("Failed to load worker key: %v", err)
+	}
 
 	// Connect to NATS
-	nc, err := nats.Connect(nats.DefaultURL)
+	nc, err := cfg.NATS.Connect(fmt.Sprintf("oracle-worker-%d", cfg.Port))
 	if err != nil {
 		log.Fatalf("Failed to connect to NATS: %v", err)
 	}
-	defer nc.Close()
 
-	log.Printf("🔗 Connected to NATS at %s", nats.DefaultURL)
+	log.Printf("🔗 Connected to NATS at %s (auth: %s, tls: %t)", cfg.NATS.URL, cfg.NATS.AuthMode(), cfg.NATS.TLSEnabled())
 
 	// Create worker instance
-	w := worker.NewWorker(*port)
+	w := worker.NewWorker(cfg.Port)
+	w.SetKey(key)
+	w.SetSubjects(cfg.Subjects)
+	if cfg.ID != "" {
+		w.SetID(cfg.ID)
+	}
+	if err := w.SetMaxInFlight(cfg.MaxInFlight); err != nil {
+		log.Fatalf("Invalid worker configuration: %v", err)
+	}
+	if cfg.TaskStream != "" {
+		w.UseTaskStream(cfg.TaskStream)
+	}
+	if cfg.SourcesPath != "" {
+		sources, err := worker.LoadSourceConfigs(cfg.SourcesPath)
+		if err != nil {
+			log.Fatalf("Failed to load data sources: %v", err)
+		}
+		if err := w.SetSources(sources); err != nil {
+			log.Fatalf("Invalid data source configuration: %v", err)
+		}
+		log.Printf("🔌 Loaded %d data sources from %s", len(sources), cfg.SourcesPath)
+	}
 
 	log.Printf("🔧 Worker %s started successfully!", w.GetID())
-	log.Printf("👂 Subscribing to oracle.tasks...")
 
-	// Subscribe to tasks and process them
-	if err := w.SubscribeTasks(nc); err != nil {
-		log.Fatalf("Failed to subscribe to tasks: %v", err)
+	// Register with the coordinator and keep the registration alive with heartbeats.
+	// A failed registration is not fatal: the first heartbeat registers the worker.
+	if err := w.Register(nc); errors.Is(err, worker.ErrDuplicateID) {
+		log.Fatalf("Failed to register worker: %v", err)
+	} else if err != nil {
+		log.Printf("⚠️  %v (will retry via heartbeats)", err)
 	}
+	// Stop taking tasks on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Wait for interrupt signal
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	<-c
+	w.StartHeartbeat(ctx, cfg.HeartbeatInterval)
+	log.Printf("👂 Subscribing to %s...", cfg.Subjects.Tasks)
+
+	// Subscribe to tasks and process them until interrupted
+	if err := w.SubscribeTasks(ctx, nc); err != nil {
+		log.Printf("❌ Task subscription ended: %v", err)
+	}
 
 	log.Printf("🛑 Shutting down worker %s...", w.GetID())
-	w.Close()
+	if err := w.Shutdown(cfg.GracePeriod); err != nil {
+		log.Printf("❌ Shutdown error: %v", err)
+	}
+	log.Printf("✅ Worker %s stopped", w.GetID())
 }
@@ -49,6 +49,36 @@ func (c *Client) SubmitOracleRequest(ctx context.Context, query string) (*models
 	return &result, nil
 }
 
+// SubmitAsyncRequest submits an oracle request without waiting for the result.
+// It returns the request ID to poll with GetRequestStatus.
+func (c *Client) SubmitAsyncRequest(ctx context.Context, query string) (string, error) {
+	req := models.OracleRequest{
+		ID:    utils.GenerateRequestID(),
+		Query: query,
+	}
+
+	log.Printf("📤 Submitting async request %s: %s", req.ID, req.Query)
+
+	var accepted struct {
+		RequestID string `json:"request_id"`
+	}
+	if err := c.makeRequest(ctx, "POST", "/requests", req, &accepted); err != nil {
+		return "", err
+	}
+
+	return accepted.RequestID, nil
+}
+
+// GetRequestStatus fetches the status of an asynchronous request
+func (c *Client) GetRequestStatus(ctx context.Context, requestID string) (*models.RequestState, error) {
+	var state models.RequestState
+	if err := c.makeRequest(ctx, "GET", "/requests/"+requestID, nil, &state); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
 // SimulateClient submits a batch of requests and logs results
 func SimulateClient(coordinatorURL string, queries []string) {
 	client := NewClient(coordinatorURL)
@@ -93,18 +123,24 @@ func SimulateClient(coordinatorURL string, queries []string) {
 // makeRequest makes an HTTP request with context
 func (c *Client) makeRequest(ctx context.Context, method string, path string, requestBody any, responseBody any) error {
 	// Marshal request body
-	reqBytes, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %v", err)
+	var body io.Reader
+	if requestBody != nil {
+		reqBytes, err := json.Marshal(requestBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %v", err)
+		}
+		body = bytes.NewBuffer(reqBytes)
 	}
 
 	// Create HTTP request with context
-	req, err := http.NewRequestWithContext(ctx, method, c.coordinatorURL+path, bytes.NewBuffer(reqBytes))
+	req, err := http.NewRequestWithContext(ctx, method, c.coordinatorURL+path, body)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %v", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	// Make request
 	resp, err := c.httpClient.Do(req)
@@ -113,17 +149,17 @@ func (c *Client) makeRequest(ctx context.Context, method string, path string, re
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		return fmt.Errorf("coordinator returned status %d", resp.StatusCode)
 	}
 
 	// Read and unmarshal response
-	body, err := io.ReadAll(resp.Body)
+	respBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	if err := json.Unmarshal(body, responseBody); err != nil {
+	if err := json.Unmarshal(respBytes, responseBody); err != nil {
 		return fmt.Errorf("failed to decode response: %v", err)
 	}
 
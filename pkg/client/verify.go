@@ -0,0 +1,80 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/signing"
+)
+
+// ErrReportNotTrusted is returned when a report fails verification
+var ErrReportNotTrusted = errors.New("oracle report not trusted")
+
+// VerifyOptions configures report verification
+type VerifyOptions struct {
+	// CoordinatorKey is the coordinator's report signing key
+	CoordinatorKey ed25519.PublicKey
+	// WorkerKeys are the worker public keys the consumer trusts, by worker ID
+	WorkerKeys map[string]ed25519.PublicKey
+	// Threshold is the number of distinct trusted workers that must attest to the final value.
+	// It must be at least 1.
+	Threshold int
+	// Tolerance is the maximum relative deviation from the final value, e.g. 0.01 for 1%
+	Tolerance float64
+}
+
+// VerifyReport checks that a result's report was signed by the coordinator and that
+// at least opts.Threshold trusted workers signed observations consistent with FinalValue.
+// It returns the verified report; callers should use its fields rather than the
+// unsigned ones on result.
+func VerifyReport(result models.OracleResult, opts VerifyOptions) (models.Report, error) {
+	if opts.Threshold < 1 {
+		return models.Report{}, fmt.Errorf("%w: threshold must be at least 1, got %d", ErrReportNotTrusted, opts.Threshold)
+	}
+	if result.Report == nil {
+		return models.Report{}, fmt.Errorf("%w: result has no signed report", ErrReportNotTrusted)
+	}
+
+	report, err := signing.OpenReport(opts.CoordinatorKey, *result.Report)
+	if err != nil {
+		return models.Report{}, fmt.Errorf("%w: coordinator signature: %v", ErrReportNotTrusted, err)
+	}
+
+	if report.RequestID != result.RequestID || report.Query != result.Query ||
+		report.FinalValue != result.FinalValue || report.Strategy != result.Strategy ||
+		!report.Timestamp.Equal(result.Timestamp) {
+		return models.Report{}, fmt.Errorf("%w: report does not match the result", ErrReportNotTrusted)
+	}
+
+	attested := make(map[string]bool)
+	for _, obs := range report.Observations {
+		pub, trusted := opts.WorkerKeys[obs.WorkerID]
+		if !trusted || obs.Err != "" || obs.RequestID != report.RequestID || obs.Query != report.Query {
+			continue
+		}
+		if signing.VerifyResult(pub, obs) != nil {
+			continue
+		}
+		if !withinTolerance(obs.Value, report.FinalValue, opts.Tolerance) {
+			continue
+		}
+		attested[obs.WorkerID] = true
+	}
+
+	if len(attested) < opts.Threshold {
+		return models.Report{}, fmt.Errorf("%w: %d of %d required workers attested to %.8g",
+			ErrReportNotTrusted, len(attested), opts.Threshold, report.FinalValue)
+	}
+	return report, nil
+}
+
+// withinTolerance reports whether value is within a relative tolerance of target
+func withinTolerance(value, target, tolerance float64) bool {
+	if target == 0 {
+		return value == 0
+	}
+	return math.Abs(value-target)/math.Abs(target) <= tolerance
+}
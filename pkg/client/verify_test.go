@@ -0,0 +1,178 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/signing"
+)
+
+// testKey returns a deterministic key pair for the given seed byte
+func testKey(b byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, ed25519.SeedSize))
+}
+
+// fixture holds a signed result together with the keys needed to verify it
+type fixture struct {
+	coordinator ed25519.PrivateKey
+	workers     map[string]ed25519.PrivateKey
+	report      models.Report
+}
+
+// newFixture signs observations from three workers; worker-3 reports an outlier
+func newFixture() fixture {
+	f := fixture{
+		coordinator: testKey(100),
+		workers:     make(map[string]ed25519.PrivateKey),
+		report: models.Report{
+			RequestID:  "req-1",
+			Query:      "BTC/USD",
+			Timestamp:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			FinalValue: 100,
+			Strategy:   "median",
+		},
+	}
+
+	for i, value := range []float64{100, 100.5, 150} {
+		id := fmt.Sprintf("worker-%d", i+1)
+		f.workers[id] = testKey(byte(i + 1))
+		obs := models.WorkerResult{
+			WorkerID:  id,
+			RequestID: f.report.RequestID,
+			Query:     f.report.Query,
+			Timestamp: f.report.Timestamp,
+			Value:     value,
+		}
+		signing.SignResult(f.workers[id], &obs)
+		f.report.Observations = append(f.report.Observations, obs)
+	}
+	return f
+}
+
+// result returns the oracle result carrying the fixture's report signed by the coordinator
+func (f fixture) result() models.OracleResult {
+	return models.OracleResult{
+		RequestID:  f.report.RequestID,
+		Query:      f.report.Query,
+		Timestamp:  f.report.Timestamp,
+		FinalValue: f.report.FinalValue,
+		Strategy:   f.report.Strategy,
+		Report:     signing.SignReport(f.coordinator, f.report),
+	}
+}
+
+// options trusts every fixture worker and the coordinator
+func (f fixture) options() VerifyOptions {
+	keys := make(map[string]ed25519.PublicKey)
+	for id, priv := range f.workers {
+		keys[id] = priv.Public().(ed25519.PublicKey)
+	}
+	return VerifyOptions{
+		CoordinatorKey: f.coordinator.Public().(ed25519.PublicKey),
+		WorkerKeys:     keys,
+		Threshold:      2,
+		Tolerance:      0.01,
+	}
+}
+
+func TestVerifyReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(f *fixture, result *models.OracleResult, opts *VerifyOptions)
+		wantErr bool
+	}{
+		{"two workers within tolerance", func(*fixture, *models.OracleResult, *VerifyOptions) {}, false},
+		{"threshold above attesting workers", func(_ *fixture, _ *models.OracleResult, opts *VerifyOptions) {
+			opts.Threshold = 3
+		}, true},
+		{"wider tolerance admits the outlier", func(_ *fixture, _ *models.OracleResult, opts *VerifyOptions) {
+			opts.Threshold = 3
+			opts.Tolerance = 0.5
+		}, false},
+		{"zero threshold", func(_ *fixture, _ *models.OracleResult, opts *VerifyOptions) {
+			opts.Threshold = 0
+		}, true},
+		{"no report", func(_ *fixture, result *models.OracleResult, _ *VerifyOptions) {
+			result.Report = nil
+		}, true},
+		{"wrong coordinator key", func(_ *fixture, _ *models.OracleResult, opts *VerifyOptions) {
+			opts.CoordinatorKey = testKey(99).Public().(ed25519.PublicKey)
+		}, true},
+		{"untrusted workers", func(_ *fixture, _ *models.OracleResult, opts *VerifyOptions) {
+			delete(opts.WorkerKeys, "worker-1")
+		}, true},
+		{"unsigned final value", func(_ *fixture, result *models.OracleResult, _ *VerifyOptions) {
+			result.FinalValue = 120
+		}, true},
+		{"unsigned query", func(_ *fixture, result *models.OracleResult, _ *VerifyOptions) {
+			result.Query = "ETH/USD"
+		}, true},
+		{"unsigned strategy", func(_ *fixture, result *models.OracleResult, _ *VerifyOptions) {
+			result.Strategy = "average"
+		}, true},
+		{"unsigned timestamp", func(_ *fixture, result *models.OracleResult, _ *VerifyOptions) {
+			result.Timestamp = result.Timestamp.Add(time.Hour)
+		}, true},
+		{"observation for another query", func(f *fixture, result *models.OracleResult, _ *VerifyOptions) {
+			obs := &f.report.Observations[1]
+			obs.Query = "ETH/USD"
+			signing.SignResult(f.workers[obs.WorkerID], obs)
+			*result = f.result()
+		}, true},
+		{"forged observation", func(f *fixture, result *models.OracleResult, _ *VerifyOptions) {
+			f.report.Observations[1].Value = 100
+			*result = f.result()
+		}, true},
+		{"duplicate observations count once", func(f *fixture, result *models.OracleResult, _ *VerifyOptions) {
+			f.report.Observations[1] = f.report.Observations[0]
+			*result = f.result()
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			result, opts := f.result(), f.options()
+			tt.modify(&f, &result, &opts)
+
+			report, err := VerifyReport(result, opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrReportNotTrusted) {
+					t.Errorf("VerifyReport() error = %v, want ErrReportNotTrusted", err)
+				}
+				return
+			}
+			if report.FinalValue != result.FinalValue || report.Query != result.Query {
+				t.Errorf("VerifyReport() = %+v, want the signed report", report)
+			}
+		})
+	}
+}
+
+func TestWithinTolerance(t *testing.T) {
+	tests := []struct {
+		value, target, tolerance float64
+		want                     bool
+	}{
+		{100, 100, 0, true},
+		{101, 100, 0.01, true},
+		{102, 100, 0.01, false},
+		{-101, -100, 0.01, true},
+		{0, 0, 0.01, true},
+		{0.001, 0, 0.01, false},
+	}
+
+	for _, tt := range tests {
+		if got := withinTolerance(tt.value, tt.target, tt.tolerance); got != tt.want {
+			t.Errorf("withinTolerance(%v, %v, %v) = %v, want %v", tt.value, tt.target, tt.tolerance, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvConfigFile names the environment variable holding the config file path
+const EnvConfigFile = "ORACLE_CONFIG"
+
+// ErrInvalidConfig is returned when a configuration value is out of range
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// envVar maps an environment variable onto a configuration field
+type envVar struct {
+	name string
+	set  func(value string) error
+}
+
+// stringEnv returns a setter for a string field
+func stringEnv(p *string) func(string) error {
+	return func(value string) error {
+		*p = value
+		return nil
+	}
+}
+
+// intEnv returns a setter for an int field
+func intEnv(p *int) func(string) error {
+	return func(value string) error {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*p = v
+		return nil
+	}
+}
+
+// floatEnv returns a setter for a float64 field
+func floatEnv(p *float64) func(string) error {
+	return func(value string) error {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*p = v
+		return nil
+	}
+}
+
+// durationEnv returns a setter for a time.Duration field
+func durationEnv(p *time.Duration) func(string) error {
+	return func(value string) error {
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*p = v
+		return nil
+	}
+}
+
+// commonEnv returns the environment variables shared by the coordinator and workers
+func commonEnv(natsCfg *NATSConfig, subjects *models.Subjects) []envVar {
+	return []envVar{
+		{"NATS_URL", stringEnv(&natsCfg.URL)},
+		{"NATS_USER", stringEnv(&natsCfg.User)},
+		{"NATS_PASSWORD", stringEnv(&natsCfg.Password)},
+		{"NATS_TOKEN", stringEnv(&natsCfg.Token)},
+		{"NATS_NKEY_SEED", stringEnv(&natsCfg.NKeySeed)},
+		{"NATS_CREDS", stringEnv(&natsCfg.CredsFile)},
+		{"NATS_TLS_CA", stringEnv(&natsCfg.TLS.CAFile)},
+		{"NATS_TLS_CERT", stringEnv(&natsCfg.TLS.CertFile)},
+		{"NATS_TLS_KEY", stringEnv(&natsCfg.TLS.KeyFile)},
+		{"ORACLE_SUBJECT_TASKS", stringEnv(&subjects.Tasks)},
+		{"ORACLE_SUBJECT_RESULTS", stringEnv(&subjects.Results)},
+		{"ORACLE_SUBJECT_REGISTER", stringEnv(&subjects.Register)},
+		{"ORACLE_SUBJECT_HEARTBEAT", stringEnv(&subjects.Heartbeat)},
+		{"ORACLE_SUBJECT_DEREGISTER", stringEnv(&subjects.Deregister)},
+		{"ORACLE_SUBJECT_FEED_PREFIX", stringEnv(&subjects.FeedPrefix)},
+	}
+}
+
+// applyEnv sets every field whose environment variable is set and not empty
+func applyEnv(vars []envVar) error {
+	for _, v := range vars {
+		value, ok := os.LookupEnv(v.name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := v.set(value); err != nil {
+			return fmt.Errorf("%w: %s=%q: %v", ErrInvalidConfig, v.name, value, err)
+		}
+	}
+	return nil
+}
+
+// loadFile decodes a YAML config file into cfg. Fields missing from the file keep their value.
+func loadFile(path string, cfg any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	return nil
+}
+
+// load layers the configuration sources onto cfg in order of increasing precedence:
+// the defaults already in cfg, the YAML file, environment variables and flags.
+// bind registers the flags for cfg on a flag set using the current values as defaults.
+func load(name string, args []string, cfg any, env []envVar, bind func(fs *flag.FlagSet)) error {
+	// A first pass only finds the config file, so flags can be bound to the
+	// values from the file and environment in the second pass
+	configPath := os.Getenv(EnvConfigFile)
+	probe := flag.NewFlagSet(name, flag.ContinueOnError)
+	probe.SetOutput(io.Discard)
+	probe.StringVar(&configPath, "config", configPath, "")
+	bind(probe)
+	probe.Parse(args)
+
+	if configPath != "" {
+		if err := loadFile(configPath, cfg); err != nil {
+			return err
+		}
+	}
+	if err := applyEnv(env); err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.String("config", configPath, "Path to a YAML config file (env "+EnvConfigFile+")")
+	bind(fs)
+	if err := fs.Parse(args); err != nil {
+		// -h prints the usage and is not a configuration error
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// validateNATS checks the NATS URL and subject names
+func validateNATS(natsCfg NATSConfig, subjects models.Subjects) error {
+	if err := natsCfg.Validate(); err != nil {
+		return err
+	}
+
+	named := map[string]string{
+		"tasks":       subjects.Tasks,
+		"results":     subjects.Results,
+		"register":    subjects.Register,
+		"heartbeat":   subjects.Heartbeat,
+		"deregister":  subjects.Deregister,
+		"feed_prefix": subjects.FeedPrefix,
+	}
+	for field, subject := range named {
+		if subject == "" || strings.ContainsAny(subject, " \t*>") {
+			return fmt.Errorf("%w: subject %s must be a non-empty subject without wildcards or spaces, got %q",
+				ErrInvalidConfig, field, subject)
+		}
+	}
+	return nil
+}
+
+// validateStreamName checks that a JetStream stream name, if set, is usable
+func validateStreamName(stream string) error {
+	if strings.ContainsAny(stream, " \t.*>/\\") {
+		return fmt.Errorf("%w: task_stream must be a valid stream name, got %q", ErrInvalidConfig, stream)
+	}
+	return nil
+}
+
+// validatePort checks that port is a usable TCP port
+func validatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("%w: port must be between 1 and 65535, got %d", ErrInvalidConfig, port)
+	}
+	return nil
+}
+
+// validatePositive checks that a duration setting is greater than zero
+func validatePositive(field string, d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("%w: %s must be positive, got %v", ErrInvalidConfig, field, d)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeConfig writes a YAML config file for the test and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "coordinator.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadCoordinatorPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+port: 9000
+strategy: median
+limits:
+  max_responses: 50
+`)
+
+	tests := []struct {
+		name         string
+		args         []string
+		env          map[string]string
+		wantPort     int
+		wantStrategy string
+		wantErr      error
+	}{
+		{"defaults", nil, nil, 8080, "average", nil},
+		{"file over defaults", []string{"-config", path}, nil, 9000, "median", nil},
+		{"config file from env", nil, map[string]string{EnvConfigFile: path}, 9000, "median", nil},
+		{"env over file", []string{"-config", path}, map[string]string{"ORACLE_PORT": "9100"}, 9100, "median", nil},
+		{"flags over env", []string{"-config", path, "-port", "9200", "-strategy", "trimmed_mean"},
+			map[string]string{"ORACLE_PORT": "9100", "ORACLE_STRATEGY": "mad"}, 9200, "trimmed_mean", nil},
+		{"malformed flag", []string{"-config", path, "-port=abc"}, nil, 0, "", ErrInvalidConfig},
+		{"unknown flag", []string{"-no-such-flag"}, nil, 0, "", ErrInvalidConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvConfigFile, "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := LoadCoordinator(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadCoordinator() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.Port != tt.wantPort {
+				t.Errorf("Port = %d, want %d", cfg.Port, tt.wantPort)
+			}
+			if cfg.Strategy != tt.wantStrategy {
+				t.Errorf("Strategy = %q, want %q", cfg.Strategy, tt.wantStrategy)
+			}
+		})
+	}
+}
+
+func TestLoadCoordinatorLimits(t *testing.T) {
+	t.Setenv(EnvConfigFile, "")
+	t.Setenv("ORACLE_LIMIT_MAX_TIMEOUT", "2m")
+	path := writeConfig(t, `
+limits:
+  min_timeout: 500ms
+  max_responses: 50
+`)
+
+	cfg, err := LoadCoordinator([]string{"-config", path, "-limit-max-responses", "25"})
+	if err != nil {
+		t.Fatalf("LoadCoordinator() error = %v", err)
+	}
+	if cfg.Limits.MinTimeout != 500*time.Millisecond {
+		t.Errorf("Limits.MinTimeout = %v, want 500ms from the file", cfg.Limits.MinTimeout)
+	}
+	if cfg.Limits.MaxTimeout != 2*time.Minute {
+		t.Errorf("Limits.MaxTimeout = %v, want 2m from the environment", cfg.Limits.MaxTimeout)
+	}
+	if cfg.Limits.MaxResponses != 25 {
+		t.Errorf("Limits.MaxResponses = %d, want 25 from the flag", cfg.Limits.MaxResponses)
+	}
+}
+
+func TestLoadCoordinatorInvalidSources(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+	}{
+		{"malformed env value", "", map[string]string{"ORACLE_PORT": "eighty"}},
+		{"malformed env duration", "", map[string]string{"ORACLE_TIMEOUT": "5"}},
+		{"malformed file", "port: [", nil},
+		{"invalid value in file", "port: 70000", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvConfigFile, "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			var args []string
+			if tt.file != "" {
+				args = []string{"-config", writeConfig(t, tt.file)}
+			}
+
+			if _, err := LoadCoordinator(args); err == nil {
+				t.Error("LoadCoordinator() returned no error")
+			}
+		})
+	}
+}
+
+func TestCoordinatorValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *CoordinatorConfig)
+		wantErr bool
+	}{
+		{"defaults", func(*CoordinatorConfig) {}, false},
+		{"shared state and task stream", func(cfg *CoordinatorConfig) {
+			cfg.StateBucket = "oracle_requests"
+			cfg.TaskStream = "ORACLE_TASKS"
+		}, false},
+		{"port out of range", func(cfg *CoordinatorConfig) { cfg.Port = 0 }, true},
+		{"id with a dot", func(cfg *CoordinatorConfig) { cfg.ID = "coord.a" }, true},
+		{"empty data path", func(cfg *CoordinatorConfig) { cfg.DataPath = "" }, true},
+		{"zero rate limit", func(cfg *CoordinatorConfig) { cfg.RateLimit = 0 }, true},
+		{"unknown strategy", func(cfg *CoordinatorConfig) { cfg.Strategy = "mode" }, true},
+		{"reputation strategy", func(cfg *CoordinatorConfig) { cfg.Strategy = "reputation" }, false},
+		{"unknown signature policy", func(cfg *CoordinatorConfig) { cfg.Signatures = "maybe" }, true},
+		{"zero timeout", func(cfg *CoordinatorConfig) { cfg.Timeout = 0 }, true},
+		{"negative lease", func(cfg *CoordinatorConfig) { cfg.LeaseTTL = -time.Second }, true},
+		{"max timeout below min timeout", func(cfg *CoordinatorConfig) {
+			cfg.Limits.MaxTimeout = cfg.Limits.MinTimeout / 2
+		}, true},
+		{"timeout above max timeout", func(cfg *CoordinatorConfig) { cfg.Timeout = cfg.Limits.MaxTimeout + time.Second }, true},
+		{"timeout below min timeout", func(cfg *CoordinatorConfig) {
+			cfg.Limits.MinTimeout = time.Second
+			cfg.Timeout = 500 * time.Millisecond
+		}, true},
+		{"zero max responses", func(cfg *CoordinatorConfig) { cfg.Limits.MaxResponses = 0 }, true},
+		{"evict before suspect", func(cfg *CoordinatorConfig) { cfg.EvictAfter = cfg.SuspectAfter / 2 }, true},
+		{"bucket with a dot", func(cfg *CoordinatorConfig) { cfg.StateBucket = "oracle.requests" }, true},
+		{"stream with a wildcard", func(cfg *CoordinatorConfig) { cfg.TaskStream = "TASKS*" }, true},
+		{"wildcard subject", func(cfg *CoordinatorConfig) { cfg.Subjects.Tasks = "oracle.>" }, true},
+		{"empty subject", func(cfg *CoordinatorConfig) { cfg.Subjects.Results = "" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultCoordinator()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("Validate() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"distributed-worker-system/pkg/coordinator"
+	"distributed-worker-system/pkg/models"
+)
+
+// CoordinatorConfig holds the coordinator's settings
+type CoordinatorConfig struct {
+	// ID names the coordinator's results subject; generated when empty
+	ID       string          `yaml:"id"`
+	NATS     NATSConfig      `yaml:"nats"`
+	Subjects models.Subjects `yaml:"subjects"`
+	Port     int             `yaml:"port"`
+	DataPath string          `yaml:"data"`
+	KeyPath  string          `yaml:"key"`
+
+	RateLimit float64 `yaml:"rate_limit"`
+	RateBurst int     `yaml:"rate_burst"`
+
+	// Limits bound the options clients may set on a request
+	Limits coordinator.RequestLimits `yaml:"limits"`
+
+	Timeout        time.Duration `yaml:"timeout"`
+	Strategy       string        `yaml:"strategy"`
+	Quorum         int           `yaml:"quorum"`
+	QuorumFraction float64       `yaml:"quorum_fraction"`
+	MinResponses   int           `yaml:"min_responses"`
+
+	Signatures      string        `yaml:"signatures"`
+	Retention       time.Duration `yaml:"retention"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	SuspectAfter    time.Duration `yaml:"suspect_after"`
+	EvictAfter      time.Duration `yaml:"evict_after"`
+
+	// StateBucket enables shared request state in a JetStream KV bucket when set
+	StateBucket string        `yaml:"state_bucket"`
+	LeaseTTL    time.Duration `yaml:"lease_ttl"`
+
+	// TaskStream publishes tasks through a JetStream stream when set
+	TaskStream string        `yaml:"task_stream"`
+	TaskMaxAge time.Duration `yaml:"task_max_age"`
+}
+
+// DefaultCoordinator returns the coordinator's built-in defaults
+func DefaultCoordinator() CoordinatorConfig {
+	return CoordinatorConfig{
+		NATS:            defaultNATS(),
+		Subjects:        models.DefaultSubjects(),
+		Port:            8080,
+		DataPath:        "data/coordinator.db",
+		KeyPath:         "data/coordinator.key",
+		RateLimit:       coordinator.DefaultRateLimit,
+		RateBurst:       coordinator.DefaultRateBurst,
+		Limits:          coordinator.DefaultRequestLimits,
+		Timeout:         coordinator.DefaultRequestOptions.Timeout(),
+		Strategy:        coordinator.DefaultRequestOptions.Strategy,
+		Quorum:          coordinator.DefaultRequestOptions.QuorumResponses,
+		QuorumFraction:  coordinator.DefaultRequestOptions.QuorumFraction,
+		MinResponses:    coordinator.DefaultRequestOptions.MinResponses,
+		Signatures:      string(coordinator.SignaturesRequired),
+		Retention:       coordinator.DefaultResultRetention,
+		ShutdownTimeout: coordinator.DefaultShutdownTimeout,
+		SuspectAfter:    coordinator.DefaultSuspectAfter,
+		EvictAfter:      coordinator.DefaultEvictAfter,
+		LeaseTTL:        coordinator.DefaultLeaseTTL,
+		TaskMaxAge:      coordinator.DefaultTaskMaxAge,
+	}
+}
+
+// LoadCoordinator builds the coordinator configuration from the defaults, an optional
+// YAML file, environment variables and the command line flags in args, in that order
+// of precedence, and validates the result
+func LoadCoordinator(args []string) (CoordinatorConfig, error) {
+	cfg := DefaultCoordinator()
+	if err := load("coordinator", args, &cfg, cfg.env(), cfg.bindFlags); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// env lists the coordinator's environment variables
+func (cfg *CoordinatorConfig) env() []envVar {
+	return append(commonEnv(&cfg.NATS, &cfg.Subjects),
+		envVar{"ORACLE_COORDINATOR_ID", stringEnv(&cfg.ID)},
+		envVar{"ORACLE_PORT", intEnv(&cfg.Port)},
+		envVar{"ORACLE_DATA", stringEnv(&cfg.DataPath)},
+		envVar{"ORACLE_KEY", stringEnv(&cfg.KeyPath)},
+		envVar{"ORACLE_RATE_LIMIT", floatEnv(&cfg.RateLimit)},
+		envVar{"ORACLE_RATE_BURST", intEnv(&cfg.RateBurst)},
+		envVar{"ORACLE_LIMIT_MIN_TIMEOUT", durationEnv(&cfg.Limits.MinTimeout)},
+		envVar{"ORACLE_LIMIT_MAX_TIMEOUT", durationEnv(&cfg.Limits.MaxTimeout)},
+		envVar{"ORACLE_LIMIT_MAX_RESPONSES", intEnv(&cfg.Limits.MaxResponses)},
+		envVar{"ORACLE_TIMEOUT", durationEnv(&cfg.Timeout)},
+		envVar{"ORACLE_STRATEGY", stringEnv(&cfg.Strategy)},
+		envVar{"ORACLE_QUORUM", intEnv(&cfg.Quorum)},
+		envVar{"ORACLE_QUORUM_FRACTION", floatEnv(&cfg.QuorumFraction)},
+		envVar{"ORACLE_MIN_RESPONSES", intEnv(&cfg.MinResponses)},
+		envVar{"ORACLE_SIGNATURES", stringEnv(&cfg.Signatures)},
+		envVar{"ORACLE_RETENTION", durationEnv(&cfg.Retention)},
+		envVar{"ORACLE_SHUTDOWN_TIMEOUT", durationEnv(&cfg.ShutdownTimeout)},
+		envVar{"ORACLE_SUSPECT_AFTER", durationEnv(&cfg.SuspectAfter)},
+		envVar{"ORACLE_EVICT_AFTER", durationEnv(&cfg.EvictAfter)},
+		envVar{"ORACLE_STATE_BUCKET", stringEnv(&cfg.StateBucket)},
+		envVar{"ORACLE_LEASE_TTL", durationEnv(&cfg.LeaseTTL)},
+		envVar{"ORACLE_TASK_STREAM", stringEnv(&cfg.TaskStream)},
+		envVar{"ORACLE_TASK_MAX_AGE", durationEnv(&cfg.TaskMaxAge)},
+	)
+}
+
+// bindFlags registers the coordinator flags on fs
+func (cfg *CoordinatorConfig) bindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&cfg.ID, "id", cfg.ID, "Coordinator ID used in its results subject (default: generated)")
+	fs.StringVar(&cfg.NATS.URL, "nats-url", cfg.NATS.URL, "NATS server URL")
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "HTTP port for the coordinator API")
+	fs.StringVar(&cfg.DataPath, "data", cfg.DataPath, "Path to the coordinator's local data store")
+	fs.StringVar(&cfg.KeyPath, "key", cfg.KeyPath, "Path to the coordinator's ed25519 report signing key")
+	fs.Float64Var(&cfg.RateLimit, "rate-limit", cfg.RateLimit, "HTTP requests per second allowed per client IP")
+	fs.IntVar(&cfg.RateBurst, "rate-burst", cfg.RateBurst, "Burst size of the per-IP rate limit")
+	fs.DurationVar(&cfg.Limits.MinTimeout, "limit-min-timeout", cfg.Limits.MinTimeout, "Shortest timeout_ms a client may request")
+	fs.DurationVar(&cfg.Limits.MaxTimeout, "limit-max-timeout", cfg.Limits.MaxTimeout, "Longest timeout_ms a client may request")
+	fs.IntVar(&cfg.Limits.MaxResponses, "limit-max-responses", cfg.Limits.MaxResponses, "Largest max_responses a client may request")
+	fs.DurationVar(&cfg.Timeout, "timeout", cfg.Timeout, "Default worker response collection timeout")
+	fs.StringVar(&cfg.Strategy, "strategy", cfg.Strategy, "Default aggregation strategy")
+	fs.IntVar(&cfg.Quorum, "quorum", cfg.Quorum, "Complete requests after this many responses (0 = use -quorum-fraction)")
+	fs.Float64Var(&cfg.QuorumFraction, "quorum-fraction", cfg.QuorumFraction, "Complete requests after this fraction of registered workers responded")
+	fs.IntVar(&cfg.MinResponses, "min-responses", cfg.MinResponses, "Fail requests with fewer successful responses than this")
+	fs.StringVar(&cfg.Signatures, "signatures", cfg.Signatures, "How to treat unsigned or invalid worker results: require, flag or off")
+	fs.DurationVar(&cfg.Retention, "retention", cfg.Retention, "How long finished requests remain queryable")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "How long in-flight requests may keep collecting results on shutdown")
+	fs.DurationVar(&cfg.SuspectAfter, "suspect-after", cfg.SuspectAfter, "Mark workers suspect after missing heartbeats for this long")
+	fs.DurationVar(&cfg.EvictAfter, "evict-after", cfg.EvictAfter, "Evict workers after missing heartbeats for this long")
+	fs.StringVar(&cfg.StateBucket, "state-bucket", cfg.StateBucket, "JetStream KV bucket for shared request state, e.g. "+coordinator.DefaultStateBucket+" (empty = in-memory only)")
+	fs.DurationVar(&cfg.LeaseTTL, "lease-ttl", cfg.LeaseTTL, "How long a replica owns a request without renewing its lease")
+	fs.StringVar(&cfg.TaskStream, "task-stream", cfg.TaskStream, "JetStream stream for durable task delivery, e.g. "+coordinator.DefaultTaskStream+" (empty = core NATS)")
+	fs.DurationVar(&cfg.TaskMaxAge, "task-max-age", cfg.TaskMaxAge, "How long undelivered tasks stay in the task stream")
+}
+
+// Validate checks the coordinator configuration for values that cannot work.
+// Request option defaults are validated further by Coordinator.SetDefaultOptions.
+func (cfg CoordinatorConfig) Validate() error {
+	if err := validateNATS(cfg.NATS, cfg.Subjects); err != nil {
+		return err
+	}
+	if err := validatePort(cfg.Port); err != nil {
+		return err
+	}
+	if strings.ContainsAny(cfg.ID, " \t.*>") {
+		return fmt.Errorf("%w: coordinator id must be a single subject token, got %q", ErrInvalidConfig, cfg.ID)
+	}
+	if cfg.DataPath == "" || cfg.KeyPath == "" {
+		return fmt.Errorf("%w: data and key paths must not be empty", ErrInvalidConfig)
+	}
+	if cfg.RateLimit <= 0 || cfg.RateBurst < 1 {
+		return fmt.Errorf("%w: rate_limit must be positive and rate_burst at least 1", ErrInvalidConfig)
+	}
+	if !knownStrategy(cfg.Strategy) {
+		return fmt.Errorf("%w: unknown aggregation strategy %q (available: %s)", ErrInvalidConfig,
+			cfg.Strategy, strings.Join(append(coordinator.AggregatorNames(), coordinator.StrategyReputation), ", "))
+	}
+	if _, err := coordinator.ParseSignaturePolicy(cfg.Signatures); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	durations := []struct {
+		field string
+		value time.Duration
+	}{
+		{"timeout", cfg.Timeout},
+		{"limits.min_timeout", cfg.Limits.MinTimeout},
+		{"retention", cfg.Retention},
+		{"shutdown_timeout", cfg.ShutdownTimeout},
+		{"suspect_after", cfg.SuspectAfter},
+		{"evict_after", cfg.EvictAfter},
+		{"lease_ttl", cfg.LeaseTTL},
+		{"task_max_age", cfg.TaskMaxAge},
+	}
+	for _, d := range durations {
+		if err := validatePositive(d.field, d.value); err != nil {
+			return err
+		}
+	}
+	if strings.ContainsAny(cfg.StateBucket, " \t.*>") {
+		return fmt.Errorf("%w: state_bucket must be a valid bucket name, got %q", ErrInvalidConfig, cfg.StateBucket)
+	}
+	if err := validateStreamName(cfg.TaskStream); err != nil {
+		return err
+	}
+	if cfg.Limits.MaxTimeout < cfg.Limits.MinTimeout {
+		return fmt.Errorf("%w: limits.max_timeout must not be shorter than limits.min_timeout", ErrInvalidConfig)
+	}
+	if cfg.Timeout < cfg.Limits.MinTimeout || cfg.Timeout > cfg.Limits.MaxTimeout {
+		return fmt.Errorf("%w: timeout %v must be between limits.min_timeout %v and limits.max_timeout %v",
+			ErrInvalidConfig, cfg.Timeout, cfg.Limits.MinTimeout, cfg.Limits.MaxTimeout)
+	}
+	if cfg.Limits.MaxResponses < 1 {
+		return fmt.Errorf("%w: limits.max_responses must be at least 1, got %d", ErrInvalidConfig, cfg.Limits.MaxResponses)
+	}
+	if cfg.EvictAfter < cfg.SuspectAfter {
+		return fmt.Errorf("%w: evict_after must not be shorter than suspect_after", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// knownStrategy reports whether strategy names a registered or built-in aggregation strategy
+func knownStrategy(strategy string) bool {
+	if strategy == coordinator.StrategyReputation {
+		return true
+	}
+	for _, name := range coordinator.AggregatorNames() {
+		if name == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestOptions returns the default request options described by the configuration
+func (cfg CoordinatorConfig) RequestOptions() models.RequestOptions {
+	return models.RequestOptions{
+		TimeoutMs:       int(cfg.Timeout.Milliseconds()),
+		Strategy:        cfg.Strategy,
+		QuorumResponses: cfg.Quorum,
+		QuorumFraction:  cfg.QuorumFraction,
+		MinResponses:    cfg.MinResponses,
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATS authentication modes reported by NATSConfig.AuthMode
+const (
+	AuthNone         = "none"
+	AuthUserPassword = "user_password"
+	AuthToken        = "token"
+	AuthNKey         = "nkey"
+	AuthCreds        = "creds"
+)
+
+// NATSConfig holds the NATS connection settings. At most one authentication
+// method may be set.
+type NATSConfig struct {
+	URL      string `yaml:"url"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Token    string `yaml:"token"`
+	// NKeySeed is the path to an NKey seed file
+	NKeySeed string `yaml:"nkey_seed"`
+	// CredsFile is the path to a JWT .creds file
+	CredsFile string    `yaml:"creds"`
+	TLS       TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig holds the TLS settings for the NATS connection
+type TLSConfig struct {
+	// CAFile verifies the server certificate against a custom CA
+	CAFile string `yaml:"ca"`
+	// CertFile and KeyFile present a client certificate to the server
+	CertFile string `yaml:"cert"`
+	KeyFile  string `yaml:"key"`
+}
+
+// defaultNATS returns the default NATS connection settings
+func defaultNATS() NATSConfig {
+	return NATSConfig{URL: nats.DefaultURL}
+}
+
+// AuthMode returns the authentication method the configuration selects
+func (n NATSConfig) AuthMode() string {
+	switch {
+	case n.CredsFile != "":
+		return AuthCreds
+	case n.NKeySeed != "":
+		return AuthNKey
+	case n.Token != "":
+		return AuthToken
+	case n.User != "":
+		return AuthUserPassword
+	}
+	return AuthNone
+}
+
+// TLSEnabled reports whether the configuration asks for a TLS connection
+func (n NATSConfig) TLSEnabled() bool {
+	return n.TLS.CAFile != "" || n.TLS.CertFile != "" || strings.HasPrefix(n.URL, "tls://")
+}
+
+// Validate checks that the NATS settings are complete and unambiguous
+func (n NATSConfig) Validate() error {
+	if n.URL == "" {
+		return fmt.Errorf("%w: nats url must not be empty", ErrInvalidConfig)
+	}
+
+	methods := 0
+	for _, set := range []bool{n.User != "" || n.Password != "", n.Token != "", n.NKeySeed != "", n.CredsFile != ""} {
+		if set {
+			methods++
+		}
+	}
+	if methods > 1 {
+		return fmt.Errorf("%w: set only one of nats user/password, token, nkey_seed or creds", ErrInvalidConfig)
+	}
+	if n.Password != "" && n.User == "" {
+		return fmt.Errorf("%w: nats password requires a user", ErrInvalidConfig)
+	}
+	if (n.TLS.CertFile == "") != (n.TLS.KeyFile == "") {
+		return fmt.Errorf("%w: nats tls cert and key must be set together", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Options returns the nats.go options for the configured authentication and TLS
+func (n NATSConfig) Options() ([]nats.Option, error) {
+	var opts []nats.Option
+
+	switch n.AuthMode() {
+	case AuthCreds:
+		opts = append(opts, nats.UserCredentials(n.CredsFile))
+	case AuthNKey:
+		opt, err := nats.NkeyOptionFromSeed(n.NKeySeed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load nkey seed: %v", err)
+		}
+		opts = append(opts, opt)
+	case AuthToken:
+		opts = append(opts, nats.Token(n.Token))
+	case AuthUserPassword:
+		opts = append(opts, nats.UserInfo(n.User, n.Password))
+	}
+
+	if n.TLSEnabled() {
+		opts = append(opts, nats.Secure())
+	}
+	if n.TLS.CAFile != "" {
+		opts = append(opts, nats.RootCAs(n.TLS.CAFile))
+	}
+	if n.TLS.CertFile != "" {
+		opts = append(opts, nats.ClientCert(n.TLS.CertFile, n.TLS.KeyFile))
+	}
+	return opts, nil
+}
+
+// Connect connects to NATS with the configured authentication and TLS.
+// name identifies the client in the server's monitoring endpoints.
+func (n NATSConfig) Connect(name string) (*nats.Conn, error) {
+	opts, err := n.Options()
+	if err != nil {
+		return nil, err
+	}
+	return nats.Connect(n.URL, append(opts, nats.Name(name))...)
+}
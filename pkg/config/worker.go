@@ -0,0 +1,102 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/worker"
+)
+
+// WorkerConfig holds a worker's settings
+type WorkerConfig struct {
+	NATS     NATSConfig      `yaml:"nats"`
+	Subjects models.Subjects `yaml:"subjects"`
+	Port     int             `yaml:"port"`
+	// ID overrides the worker ID derived from the key
+	ID string `yaml:"id"`
+	// KeyPath defaults to data/worker-<port>.key
+	KeyPath     string `yaml:"key"`
+	SourcesPath string `yaml:"sources"`
+	// TaskStream consumes tasks from a JetStream stream when set
+	TaskStream string `yaml:"task_stream"`
+
+	MaxInFlight       int           `yaml:"max_in_flight"`
+	GracePeriod       time.Duration `yaml:"grace_period"`
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`
+}
+
+// DefaultWorker returns the worker's built-in defaults
+func DefaultWorker() WorkerConfig {
+	return WorkerConfig{
+		NATS:              defaultNATS(),
+		Subjects:          models.DefaultSubjects(),
+		Port:              8081,
+		MaxInFlight:       worker.DefaultMaxInFlight,
+		GracePeriod:       worker.DefaultGracePeriod,
+		HeartbeatInterval: worker.DefaultHeartbeatInterval,
+	}
+}
+
+// LoadWorker builds the worker configuration from the defaults, an optional YAML
+// file, environment variables and the command line flags in args, in that order
+// of precedence, and validates the result
+func LoadWorker(args []string) (WorkerConfig, error) {
+	cfg := DefaultWorker()
+	if err := load("worker", args, &cfg, cfg.env(), cfg.bindFlags); err != nil {
+		return cfg, err
+	}
+
+	if cfg.KeyPath == "" {
+		cfg.KeyPath = fmt.Sprintf("data/worker-%d.key", cfg.Port)
+	}
+	return cfg, cfg.Validate()
+}
+
+// env lists the worker's environment variables
+func (cfg *WorkerConfig) env() []envVar {
+	return append(commonEnv(&cfg.NATS, &cfg.Subjects),
+		envVar{"WORKER_ID", stringEnv(&cfg.ID)},
+		envVar{"WORKER_PORT", intEnv(&cfg.Port)},
+		envVar{"WORKER_KEY", stringEnv(&cfg.KeyPath)},
+		envVar{"WORKER_SOURCES", stringEnv(&cfg.SourcesPath)},
+		envVar{"WORKER_MAX_IN_FLIGHT", intEnv(&cfg.MaxInFlight)},
+		envVar{"WORKER_GRACE_PERIOD", durationEnv(&cfg.GracePeriod)},
+		envVar{"WORKER_HEARTBEAT_INTERVAL", durationEnv(&cfg.HeartbeatInterval)},
+		envVar{"ORACLE_TASK_STREAM", stringEnv(&cfg.TaskStream)},
+	)
+}
+
+// bindFlags registers the worker flags on fs
+func (cfg *WorkerConfig) bindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&cfg.NATS.URL, "nats-url", cfg.NATS.URL, "NATS server URL")
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "Port for the worker (used for identification)")
+	fs.StringVar(&cfg.ID, "id", cfg.ID, "Worker ID (default: derived from the worker key)")
+	fs.StringVar(&cfg.KeyPath, "key", cfg.KeyPath, "Path to the worker's ed25519 key file (default: data/worker-<port>.key)")
+	fs.StringVar(&cfg.SourcesPath, "sources", cfg.SourcesPath, "Path to a JSON file of data source adapters (default: simulated)")
+	fs.IntVar(&cfg.MaxInFlight, "max-in-flight", cfg.MaxInFlight, "Maximum number of tasks processed concurrently")
+	fs.DurationVar(&cfg.GracePeriod, "grace-period", cfg.GracePeriod, "How long to wait for in-flight tasks on shutdown")
+	fs.DurationVar(&cfg.HeartbeatInterval, "heartbeat-interval", cfg.HeartbeatInterval, "How often to send heartbeats to the coordinator")
+	fs.StringVar(&cfg.TaskStream, "task-stream", cfg.TaskStream, "JetStream stream to consume tasks from (empty = core NATS)")
+}
+
+// Validate checks the worker configuration for values that cannot work
+func (cfg WorkerConfig) Validate() error {
+	if err := validateNATS(cfg.NATS, cfg.Subjects); err != nil {
+		return err
+	}
+	if err := validatePort(cfg.Port); err != nil {
+		return err
+	}
+	if err := validateStreamName(cfg.TaskStream); err != nil {
+		return err
+	}
+	if cfg.MaxInFlight < 1 {
+		return fmt.Errorf("%w: max_in_flight must be at least 1, got %d", ErrInvalidConfig, cfg.MaxInFlight)
+	}
+	if err := validatePositive("grace_period", cfg.GracePeriod); err != nil {
+		return err
+	}
+	return validatePositive("heartbeat_interval", cfg.HeartbeatInterval)
+}
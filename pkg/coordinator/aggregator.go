@@ -1,37 +1,327 @@
 package coordinator
 
 import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
 
 	"distributed-worker-system/pkg/models"
 )
 
+// Aggregation strategy names
+const (
+	StrategyAverage      = "average"
+	StrategyMedian       = "median"
+	StrategyTrimmedMean  = "trimmed_mean"
+	StrategyMAD          = "mad"
+	StrategyMajorityVote = "majority_vote"
+	StrategyReputation   = "reputation"
+)
+
+// DefaultStrategy is used when a request does not specify a strategy
+const DefaultStrategy = StrategyAverage
+
+const (
+	// trimFraction is the share of values dropped from each end by the trimmed mean
+	trimFraction = 0.2
+	// madThreshold is how many scaled MADs a value may sit from the median before it is rejected
+	madThreshold = 3.0
+	// madScale makes the MAD a consistent estimator of the standard deviation
+	madScale = 1.4826
+	// neutralWeight is the reputation weight given to workers without any history
+	neutralWeight = 0.5
+	// minWeight keeps a worker with a zero score from being ignored entirely
+	minWeight = 0.01
+)
+
+// successfulValues returns the values of successful worker results
+func successfulValues(results []models.WorkerResult) []float64 {
+	values := make([]float64, 0, len(results))
+	for _, result := range results {
+		if result.Err == "" { // Only count successful results
+			values = append(values, result.Value)
+		}
+	}
+	return values
+}
+
+// mean returns the arithmetic mean of values
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// median returns the median of values without modifying the input
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
 // aggregateAverage computes the average of successful worker results
 func aggregateAverage(results []models.WorkerResult) float64 {
-	if len(results) == 0 {
+	return mean(successfulValues(results))
+}
+
+// aggregateMedian computes the median of successful worker results
+func aggregateMedian(results []models.WorkerResult) float64 {
+	return median(successfulValues(results))
+}
+
+// aggregateTrimmedMean drops the highest and lowest trimFraction of values and averages
+// the rest. At least one value is dropped from each end once there are three values,
+// so a single outlier cannot move the result.
+func aggregateTrimmedMean(results []models.WorkerResult) float64 {
+	values := successfulValues(results)
+	if len(values) == 0 {
 		return 0.0
 	}
 
-	var sum float64
-	var count int
+	sort.Float64s(values)
+	trim := int(float64(len(values)) * trimFraction)
+	if trim == 0 && len(values) >= 3 {
+		trim = 1
+	}
+	return mean(values[trim : len(values)-trim])
+}
+
+// aggregateMAD rejects values further than madThreshold scaled median absolute
+// deviations from the median, then averages the remaining values
+func aggregateMAD(results []models.WorkerResult) float64 {
+	values := successfulValues(results)
+	if len(values) == 0 {
+		return 0.0
+	}
+
+	med := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - med)
+	}
+
+	mad := median(deviations) * madScale
+	if mad == 0 {
+		// At least half the workers agree exactly; trust the median
+		return med
+	}
+
+	var kept []float64
+	for _, v := range values {
+		if math.Abs(v-med)/mad <= madThreshold {
+			kept = append(kept, v)
+		}
+	}
+	return mean(kept)
+}
+
+// aggregateMajorityVote returns the most common value after rounding to two decimals.
+// Ties are broken in favour of the value closest to the median, then the lower value.
+func aggregateMajorityVote(results []models.WorkerResult) float64 {
+	values := successfulValues(results)
+	if len(values) == 0 {
+		return 0.0
+	}
+
+	counts := make(map[float64]int)
+	for _, v := range values {
+		counts[math.Round(v*100)/100]++
+	}
+
+	// Candidates are visited in ascending order, so an equally close higher value
+	// never replaces a lower one and the result does not depend on map order
+	candidates := make([]float64, 0, len(counts))
+	for v := range counts {
+		candidates = append(candidates, v)
+	}
+	sort.Float64s(candidates)
 
+	med := median(values)
+	var best float64
+	bestCount := 0
+	for _, v := range candidates {
+		count := counts[v]
+		if count > bestCount || (count == bestCount && math.Abs(v-med) < math.Abs(best-med)) {
+			best = v
+			bestCount = count
+		}
+	}
+	return best
+}
+
+// reputationAggregator weights each successful value by the worker's reliability score
+type reputationAggregator struct {
+	tracker *ReliabilityTracker
+}
+
+// NewReputationAggregator creates an aggregator that computes a reliability-weighted average
+func NewReputationAggregator(tracker *ReliabilityTracker) Aggregator {
+	return reputationAggregator{tracker: tracker}
+}
+
+func (a reputationAggregator) Name() string { return StrategyReputation }
+
+func (a reputationAggregator) Aggregate(results []models.WorkerResult) float64 {
+	var weightedSum, totalWeight float64
 	for _, result := range results {
-		if result.Err == "" { // Only count successful results
-			sum += result.Value
-			count++
+		if result.Err != "" {
+			continue
 		}
+
+		weight, known := a.tracker.Score(result.WorkerID)
+		if !known {
+			weight = neutralWeight
+		}
+		weight = math.Max(weight, minWeight)
+
+		weightedSum += weight * result.Value
+		totalWeight += weight
 	}
 
-	if count == 0 {
+	if totalWeight == 0 {
 		return 0.0
 	}
+	return weightedSum / totalWeight
+}
+
+// Aggregator combines worker results into a single final value
+type Aggregator interface {
+	// Name returns the strategy name clients use to select this aggregator
+	Name() string
+	// Aggregate computes the final value from the collected worker results
+	Aggregate(results []models.WorkerResult) float64
+}
+
+// funcAggregator adapts a plain function to the Aggregator interface
+type funcAggregator struct {
+	name string
+	fn   func(results []models.WorkerResult) float64
+}
+
+func (a funcAggregator) Name() string { return a.name }
+
+func (a funcAggregator) Aggregate(results []models.WorkerResult) float64 { return a.fn(results) }
+
+// NewAggregator creates an Aggregator from a name and an aggregation function
+func NewAggregator(name string, fn func(results []models.WorkerResult) float64) Aggregator {
+	return funcAggregator{name: name, fn: fn}
+}
+
+// AggregatorRegistry holds aggregation strategies by name
+type AggregatorRegistry struct {
+	aggregators map[string]Aggregator
+	mutex       sync.RWMutex
+}
+
+// NewAggregatorRegistry creates an empty aggregator registry
+func NewAggregatorRegistry() *AggregatorRegistry {
+	return &AggregatorRegistry{
+		aggregators: make(map[string]Aggregator),
+	}
+}
+
+// Register adds an aggregator to the registry. Names must be unique.
+func (r *AggregatorRegistry) Register(a Aggregator) error {
+	if a == nil || a.Name() == "" {
+		return fmt.Errorf("aggregator must have a name")
+	}
 
-	return sum / float64(count)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.aggregators[a.Name()]; exists {
+		return fmt.Errorf("aggregator %q is already registered", a.Name())
+	}
+	r.aggregators[a.Name()] = a
+	return nil
 }
 
+// Get returns the aggregator registered under name
+func (r *AggregatorRegistry) Get(name string) (Aggregator, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
 
-// AggregateResults aggregates worker results using the specified strategy
-func AggregateResults(results []models.WorkerResult, strategy string) float64 {
-	
-	return aggregateAverage(results)
+	a, exists := r.aggregators[name]
+	return a, exists
+}
+
+// Names returns the sorted names of all registered aggregators
+func (r *AggregatorRegistry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.aggregators))
+	for name := range r.aggregators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Aggregate runs the named strategy over results. An empty name selects DefaultStrategy.
+func (r *AggregatorRegistry) Aggregate(results []models.WorkerResult, strategy string) (float64, error) {
+	if strategy == "" {
+		strategy = DefaultStrategy
+	}
+
+	a, exists := r.Get(strategy)
+	if !exists {
+		return 0.0, fmt.Errorf("unknown aggregation strategy %q", strategy)
+	}
+	return a.Aggregate(results), nil
+}
+
+// clone returns a copy of the registry so it can be extended independently
+func (r *AggregatorRegistry) clone() *AggregatorRegistry {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	copied := NewAggregatorRegistry()
+	for name, a := range r.aggregators {
+		copied.aggregators[name] = a
+	}
+	return copied
+}
+
+// defaultAggregators holds the built-in strategies and any registered via RegisterAggregator
+var defaultAggregators = func() *AggregatorRegistry {
+	r := NewAggregatorRegistry()
+	r.aggregators[StrategyAverage] = NewAggregator(StrategyAverage, aggregateAverage)
+	r.aggregators[StrategyMedian] = NewAggregator(StrategyMedian, aggregateMedian)
+	r.aggregators[StrategyTrimmedMean] = NewAggregator(StrategyTrimmedMean, aggregateTrimmedMean)
+	r.aggregators[StrategyMAD] = NewAggregator(StrategyMAD, aggregateMAD)
+	r.aggregators[StrategyMajorityVote] = NewAggregator(StrategyMajorityVote, aggregateMajorityVote)
+	return r
+}()
+
+// RegisterAggregator adds a custom strategy to the default registry.
+// Coordinators created afterwards can use it by name.
+func RegisterAggregator(a Aggregator) error {
+	return defaultAggregators.Register(a)
+}
+
+// AggregatorNames returns the names of all strategies in the default registry
+func AggregatorNames() []string {
+	return defaultAggregators.Names()
+}
 
+// AggregateResults aggregates worker results using the named strategy from the default registry
+func AggregateResults(results []models.WorkerResult, strategy string) (float64, error) {
+	return defaultAggregators.Aggregate(results, strategy)
 }
@@ -0,0 +1,93 @@
+package coordinator
+
+import (
+	"math"
+	"testing"
+
+	"distributed-worker-system/pkg/models"
+)
+
+// resultsOf builds successful worker results with the given values
+func resultsOf(values ...float64) []models.WorkerResult {
+	results := make([]models.WorkerResult, len(values))
+	for i, v := range values {
+		results[i] = models.WorkerResult{WorkerID: string(rune('a' + i)), Value: v}
+	}
+	return results
+}
+
+func TestAggregateResults(t *testing.T) {
+	failed := models.WorkerResult{WorkerID: "failed", Value: 1e6, Err: "upstream error"}
+
+	tests := []struct {
+		name     string
+		strategy string
+		results  []models.WorkerResult
+		want     float64
+	}{
+		{"default is average", "", resultsOf(1, 2, 3), 2},
+		{"average skips failures", StrategyAverage, append(resultsOf(1, 2, 3), failed), 2},
+		{"average of nothing", StrategyAverage, nil, 0},
+		{"median odd", StrategyMedian, resultsOf(3, 1, 2), 2},
+		{"median even", StrategyMedian, resultsOf(3, 1, 2, 10), 2.5},
+		{"trimmed mean drops one outlier of three", StrategyTrimmedMean, resultsOf(100, 101, 1e9), 101},
+		{"trimmed mean drops a fifth from each end", StrategyTrimmedMean, resultsOf(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 5.5},
+		{"trimmed mean keeps two values", StrategyTrimmedMean, resultsOf(5, 7), 6},
+		{"mad rejects an outlier", StrategyMAD, resultsOf(10, 10.1, 9.9, 10, 1000), 10},
+		{"mad trusts an exact majority", StrategyMAD, resultsOf(5, 5, 5, 9), 5},
+		{"majority vote rounds to cents", StrategyMajorityVote, resultsOf(1.001, 1.004, 2), 1},
+		{"majority vote tie goes to the median", StrategyMajorityVote, resultsOf(1, 2, 3), 2},
+		{"majority vote even tie goes to the lower value", StrategyMajorityVote, resultsOf(1, 2, 3, 4), 2},
+		{"majority vote even tie in any order", StrategyMajorityVote, resultsOf(4, 3, 2, 1, 3, 2), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat so results that depend on map iteration order show up
+			for i := 0; i < 50; i++ {
+				got, err := AggregateResults(tt.results, tt.strategy)
+				if err != nil {
+					t.Fatalf("AggregateResults() error = %v", err)
+				}
+				if math.Abs(got-tt.want) > 1e-9 {
+					t.Fatalf("AggregateResults() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregateUnknownStrategy(t *testing.T) {
+	if _, err := AggregateResults(resultsOf(1), "nonexistent"); err == nil {
+		t.Error("AggregateResults() with an unknown strategy returned no error")
+	}
+}
+
+func TestAggregatorRegistryRegister(t *testing.T) {
+	registry := defaultAggregators.clone()
+	first := NewAggregator("first", func([]models.WorkerResult) float64 { return 1 })
+
+	tests := []struct {
+		name    string
+		agg     Aggregator
+		wantErr bool
+	}{
+		{"new name", first, false},
+		{"duplicate name", first, true},
+		{"built-in name", NewAggregator(StrategyMedian, aggregateAverage), true},
+		{"empty name", NewAggregator("", aggregateAverage), true},
+		{"nil aggregator", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := registry.Register(tt.agg); (err != nil) != tt.wantErr {
+				t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, exists := defaultAggregators.Get("first"); exists {
+		t.Error("registering on a clone changed the default registry")
+	}
+}
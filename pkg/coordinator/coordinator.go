@@ -2,7 +2,10 @@ package coordinator
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,6 +13,7 @@ import (
 	"time"
 
 	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/store"
 	"distributed-worker-system/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -18,40 +22,155 @@ import (
 
 // Coordinator manages workers, tasks, and aggregation using NATS
 type Coordinator struct {
+	id          string
 	nc          *nats.Conn
 	port        int
-	pendingReqs map[string]chan models.WorkerResult
+	pendingReqs map[string]*pendingRequest
 	pendingMux  sync.RWMutex
 	resultsSub  *nats.Subscription
+	aggregators *AggregatorRegistry
+	registry    *WorkerRegistry
+	stats       *ReliabilityTracker
+	metrics     *ResultMetrics
+	requests    *RequestTracker
+	feeds       *FeedScheduler
+	history     *ResultHistory
+	defaults    models.RequestOptions
+	limits      RequestLimits
+	signatures  SignaturePolicy
+	key         ed25519.PrivateKey
+	shared      *SharedState
+	taskJS      nats.JetStreamContext
+	subjects    models.Subjects
+	natsAuth    string
+	natsMTLS    bool
+	rateLimit   float64
+	rateBurst   int
+	server      *http.Server
+	// inFlight counts SubmitRequest calls that have not returned yet
+	inFlight     sync.WaitGroup
+	shutdownMux  sync.Mutex
+	shuttingDown bool
+	// stopping is closed when shutdown cuts in-flight requests short
+	stopping chan struct{}
 }
 
 // NewCoordinator initializes coordinator with NATS connection
 func NewCoordinator(nc *nats.Conn, port int) *Coordinator {
-	return &Coordinator{
+	c := &Coordinator{
+		id:          utils.GenerateCoordinatorID(),
 		nc:          nc,
 		port:        port,
-		pendingReqs: make(map[string]chan models.WorkerResult),
+		pendingReqs: make(map[string]*pendingRequest),
+		aggregators: defaultAggregators.clone(),
+		registry:    NewWorkerRegistry(DefaultSuspectAfter, DefaultEvictAfter),
+		stats:       NewReliabilityTracker(),
+		metrics:     &ResultMetrics{},
+		requests:    NewRequestTracker(DefaultResultRetention),
+		defaults:    DefaultRequestOptions,
+		limits:      DefaultRequestLimits,
+		signatures:  SignaturesRequired,
+		subjects:    models.DefaultSubjects(),
+		rateLimit:   DefaultRateLimit,
+		rateBurst:   DefaultRateBurst,
+		stopping:    make(chan struct{}),
 	}
+	c.feeds = NewFeedScheduler(c)
+	c.aggregators.Register(NewReputationAggregator(c.stats))
+	return c
+}
+
+// ID returns the coordinator's ID
+func (c *Coordinator) ID() string {
+	return c.id
+}
+
+// SetID sets the coordinator's ID, which names its results subject.
+// It must be called before the coordinator subscribes.
+func (c *Coordinator) SetID(id string) {
+	c.id = id
+}
+
+// resultsSubject returns the subject workers reply to for this coordinator's requests
+func (c *Coordinator) resultsSubject() string {
+	return c.subjects.Results + "." + c.id
+}
+
+// SetSubjects sets the NATS subjects the coordinator publishes and subscribes on.
+// It must be called before the coordinator subscribes.
+func (c *Coordinator) SetSubjects(subjects models.Subjects) {
+	c.subjects = subjects
+}
+
+// SetNATSSecurity records how the coordinator authenticates to NATS and whether it
+// presents a client certificate, so /health can report it
+func (c *Coordinator) SetNATSSecurity(authMode string, clientCert bool) {
+	c.natsAuth = authMode
+	c.natsMTLS = clientCert
+}
+
+// SetRateLimit sets the per-IP HTTP rate limit. It must be called before StartHTTPServer.
+func (c *Coordinator) SetRateLimit(requestsPerSecond float64, burst int) {
+	c.rateLimit = requestsPerSecond
+	c.rateBurst = burst
+}
+
+// SetWorkerTimeouts sets how long a worker may miss heartbeats before it is
+// marked suspect and evicted. It must be called before SubscribeWorkers.
+func (c *Coordinator) SetWorkerTimeouts(suspectAfter, evictAfter time.Duration) {
+	c.registry.suspectAfter = suspectAfter
+	c.registry.evictAfter = evictAfter
+}
+
+// UseStore persists pinned worker keys, reliability stats, feed definitions and result history in st
+func (c *Coordinator) UseStore(st *store.Store) error {
+	if err := c.registry.Persist(st); err != nil {
+		return fmt.Errorf("failed to load worker keys: %v", err)
+	}
+	if err := c.stats.Persist(st); err != nil {
+		return fmt.Errorf("failed to load worker stats: %v", err)
+	}
+	if err := c.feeds.Persist(st); err != nil {
+		return fmt.Errorf("failed to load feeds: %v", err)
+	}
+	c.history = NewResultHistory(st)
+	return nil
+}
+
+// RegisterAggregator adds a custom aggregation strategy to this coordinator
+func (c *Coordinator) RegisterAggregator(a Aggregator) error {
+	return c.aggregators.Register(a)
 }
 
 // PublishTask sends an oracle request into NATS
 func (c *Coordinator) PublishTask(req models.OracleRequest) error {
+	if req.ReplyTo == "" {
+		req.ReplyTo = c.resultsSubject()
+	}
+
 	reqBytes, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %v", err)
 	}
 
-	if err := c.nc.Publish("oracle.tasks", reqBytes); err != nil {
+	if c.taskJS != nil {
+		// Wait for the stream to store the task so it reaches reconnecting workers
+		if _, err := c.taskJS.Publish(c.subjects.Tasks, reqBytes); err != nil {
+			return fmt.Errorf("failed to publish task to stream: %v", err)
+		}
+	} else if err := c.nc.Publish(c.subjects.Tasks, reqBytes); err != nil {
 		return fmt.Errorf("failed to publish task: %v", err)
 	}
 
-	log.Printf("📤 Published task %s to oracle.tasks", req.ID)
+	log.Printf("📤 Published task %s to %s", req.ID, c.subjects.Tasks)
 	return nil
 }
 
-// SubscribeResults listens on NATS for worker results
+// SubscribeResults listens on NATS for worker results. Each coordinator has its own
+// results subject, so coordinators sharing a NATS cluster only see their own results.
 func (c *Coordinator) SubscribeResults(ctx context.Context) error {
-	sub, err := c.nc.Subscribe("oracle.results", func(msg *nats.Msg) {
+	subject := c.resultsSubject()
+	sub, err := c.nc.Subscribe(subject, func(msg *nats.Msg) {
 		var result models.WorkerResult
 		if err := json.Unmarshal(msg.Data, &result); err != nil {
 			log.Printf("❌ Failed to unmarshal worker result: %v", err)
@@ -62,108 +181,595 @@ func (c *Coordinator) SubscribeResults(ctx context.Context) error {
 		c.handleWorkerResult(result)
 	})
 	if err != nil {
-		return fmt.Errorf("failed to subscribe to oracle.results: %v", err)
+		return fmt.Errorf("failed to subscribe to %s: %v", subject, err)
 	}
 
 	c.resultsSub = sub
-	log.Printf("👂 Subscribed to oracle.results")
+	log.Printf("👂 Subscribed to %s", subject)
 
-	// Keep subscription alive
+	if c.shared != nil {
+		if err := c.startFailover(ctx); err != nil {
+			sub.Unsubscribe()
+			return err
+		}
+	}
+
+	// Keep subscription alive. Shutdown may already have unsubscribed.
 	<-ctx.Done()
+	if !sub.IsValid() {
+		return nil
+	}
 	return sub.Unsubscribe()
 }
 
+// SubscribeWorkers listens on NATS for worker registrations and heartbeats
+// and evicts workers that stop sending heartbeats
+func (c *Coordinator) SubscribeWorkers(ctx context.Context) error {
+	registerSub, err := c.nc.Subscribe(c.subjects.Register, func(msg *nats.Msg) {
+		var req models.RegisterRequest
+		if err := json.Unmarshal(msg.Data, &req); err != nil || req.ID == "" {
+			log.Printf("❌ Invalid worker registration: %v", err)
+			c.respondRegistration(msg, models.RegisterResponse{
+				Status:  "rejected",
+				Message: "registration must include a worker id",
+			})
+			return
+		}
+
+		if _, err := c.registry.Register(req); err != nil {
+			log.Printf("🚫 Rejected registration from worker %s: %v", req.ID, err)
+			resp := models.RegisterResponse{
+				Status:  "rejected",
+				Message: err.Error(),
+			}
+			if errors.Is(err, ErrDuplicateWorker) {
+				resp.Reason = models.RegisterReasonDuplicate
+			}
+			c.respondRegistration(msg, resp)
+			return
+		}
+		log.Printf("🆕 Worker %s registered (%s)", req.ID, req.Endpoint)
+		c.respondRegistration(msg, models.RegisterResponse{
+			Status:  "registered",
+			Message: fmt.Sprintf("Worker %s successfully registered", req.ID),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %v", c.subjects.Register, err)
+	}
+
+	heartbeatSub, err := c.nc.Subscribe(c.subjects.Heartbeat, func(msg *nats.Msg) {
+		var hb models.Heartbeat
+		if err := json.Unmarshal(msg.Data, &hb); err != nil || hb.ID == "" {
+			log.Printf("❌ Invalid worker heartbeat: %v", err)
+			return
+		}
+
+		known, err := c.registry.Heartbeat(hb)
+		if err != nil {
+			log.Printf("🚫 Rejected heartbeat from worker %s: %v", hb.ID, err)
+			return
+		}
+		if !known {
+			log.Printf("🆕 Worker %s registered via heartbeat (%s)", hb.ID, hb.Endpoint)
+		}
+	})
+	if err != nil {
+		registerSub.Unsubscribe()
+		return fmt.Errorf("failed to subscribe to %s: %v", c.subjects.Heartbeat, err)
+	}
+
+	deregisterSub, err := c.nc.Subscribe(c.subjects.Deregister, func(msg *nats.Msg) {
+		var dereg models.Deregistration
+		if err := json.Unmarshal(msg.Data, &dereg); err != nil || dereg.ID == "" {
+			log.Printf("❌ Invalid worker deregistration: %v", err)
+			return
+		}
+
+		removed, err := c.registry.Deregister(dereg)
+		if err != nil {
+			log.Printf("🚫 Rejected deregistration from worker %s: %v", dereg.ID, err)
+			return
+		}
+		if removed {
+			log.Printf("👋 Worker %s deregistered", dereg.ID)
+		}
+	})
+	if err != nil {
+		registerSub.Unsubscribe()
+		heartbeatSub.Unsubscribe()
+		return fmt.Errorf("failed to subscribe to %s: %v", c.subjects.Deregister, err)
+	}
+
+	c.registry.StartEviction(ctx, func(id string) {
+		log.Printf("💀 Worker %s evicted after missing heartbeats", id)
+	})
+	log.Printf("👂 Subscribed to %s, %s and %s", c.subjects.Register, c.subjects.Heartbeat, c.subjects.Deregister)
+
+	// Keep subscriptions alive
+	<-ctx.Done()
+	registerSub.Unsubscribe()
+	deregisterSub.Unsubscribe()
+	return heartbeatSub.Unsubscribe()
+}
+
+// respondRegistration replies to a worker registration request
+func (c *Coordinator) respondRegistration(msg *nats.Msg, resp models.RegisterResponse) {
+	if msg.Reply == "" {
+		return
+	}
+
+	respBytes, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("❌ Failed to marshal registration response: %v", err)
+		return
+	}
+	if err := msg.Respond(respBytes); err != nil {
+		log.Printf("❌ Failed to respond to registration: %v", err)
+	}
+}
+
 // handleWorkerResult processes incoming worker results
 func (c *Coordinator) handleWorkerResult(result models.WorkerResult) {
+	if !c.verifyResult(&result) {
+		return
+	}
+
+	// A second process running under a live worker's ID shares its key, so only
+	// results from the instance holding the registration are accepted
+	if info, registered := c.registry.Get(result.WorkerID); registered && info.InstanceID != result.InstanceID {
+		log.Printf("🚫 Dropping result from %s for request %s: instance %s does not hold the registration",
+			result.WorkerID, result.RequestID, result.InstanceID)
+		return
+	}
+
 	c.pendingMux.RLock()
-	ch, exists := c.pendingReqs[result.RequestID]
+	pending, exists := c.pendingReqs[result.RequestID]
 	c.pendingMux.RUnlock()
 
+	if exists && !pending.accepts(result) {
+		log.Printf("🚫 Dropping result from %s for request %s: query %q observed at %s does not match the request",
+			result.WorkerID, result.RequestID, result.Query, result.Timestamp.Format(time.RFC3339))
+		return
+	}
+
 	if !exists {
+		if _, known := c.requests.Get(result.RequestID); known {
+			c.recordLate(result)
+			return
+		}
+		c.metrics.add(&c.metrics.unknown)
 		log.Printf("⚠️  Received result for unknown request %s", result.RequestID)
 		return
 	}
 
+	if result.Saturated {
+		log.Printf("🚦 Worker %s is saturated and skipped request %s", result.WorkerID, result.RequestID)
+	}
+
 	// Send result to waiting goroutine
 	select {
-	case ch <- result:
+	case pending.results <- result:
+		c.metrics.add(&c.metrics.received)
+		return
 	default:
-		log.Printf("⚠️  Channel full for request %s", result.RequestID)
 	}
+
+	// The buffer is sized for the registered workers, so it only fills up when the
+	// collector falls behind. Results from registered workers are never dropped:
+	// they are handed over without blocking the results subscription.
+	if _, registered := c.registry.Get(result.WorkerID); !registered {
+		c.metrics.add(&c.metrics.dropped)
+		log.Printf("⚠️  Result buffer full for request %s, dropping result from unregistered worker %s",
+			result.RequestID, result.WorkerID)
+		return
+	}
+	go func() {
+		select {
+		case pending.results <- result:
+			c.metrics.add(&c.metrics.received)
+		case <-pending.done:
+			c.recordLate(result)
+		}
+	}()
 }
 
-// SubmitRequest submits an oracle request and waits for results (with timeout)
-func (c *Coordinator) SubmitRequest(ctx context.Context, req models.OracleRequest) models.OracleResult {
+// recordLate accounts for a result that arrived after its request stopped collecting
+func (c *Coordinator) recordLate(result models.WorkerResult) {
+	c.metrics.add(&c.metrics.late)
+	c.stats.RecordLate(result)
+	log.Printf("🐢 Late result from worker %s for request %s", result.WorkerID, result.RequestID)
+}
+
+// SubmitRequest submits an oracle request and waits for results until the quorum
+// is reached or the collection timeout expires. Request IDs must be unique among
+// the requests the coordinator still tracks.
+func (c *Coordinator) SubmitRequest(ctx context.Context, req models.OracleRequest) (models.OracleResult, error) {
+	if !c.requests.Track(req) {
+		err := fmt.Errorf("%w: %s", ErrDuplicateRequest, req.ID)
+		return models.OracleResult{
+			RequestID:       req.ID,
+			Query:           req.Query,
+			Timestamp:       time.Now(),
+			WorkerResponses: []models.WorkerResult{},
+			ReliabilityNote: err.Error(),
+		}, err
+	}
+	return c.submitTracked(ctx, req)
+}
+
+// submitTracked runs a request that is already registered with the request tracker
+func (c *Coordinator) submitTracked(ctx context.Context, req models.OracleRequest) (models.OracleResult, error) {
+	if !c.beginRequest() {
+		result := models.OracleResult{
+			RequestID:       req.ID,
+			Query:           req.Query,
+			Timestamp:       time.Now(),
+			WorkerResponses: []models.WorkerResult{},
+			ReliabilityNote: ErrCoordinatorStopping.Error(),
+		}
+		c.requests.Finish(req.ID, result, ErrCoordinatorStopping)
+		return result, ErrCoordinatorStopping
+	}
+	defer c.inFlight.Done()
+
+	result, err := c.processRequest(ctx, req)
+	c.finishTracked(req.ID, result, err)
+	return result, err
+}
+
+// finishTracked records the outcome of a tracked request. A request taken over by
+// another coordinator is forgotten instead, so GET /requests/{id} reads the
+// outcome from the shared state.
+func (c *Coordinator) finishTracked(id string, result models.OracleResult, err error) {
+	if errors.Is(err, ErrLeaseLost) {
+		c.requests.Forget(id)
+		return
+	}
+	c.requests.Finish(id, result, err)
+}
+
+// processRequest publishes the task, collects worker results and aggregates them
+func (c *Coordinator) processRequest(ctx context.Context, req models.OracleRequest) (models.OracleResult, error) {
 	log.Printf("🚀 Processing request %s: %s", req.ID, req.Query)
 
-	// Create channel for this request
-	resultChan := make(chan models.WorkerResult, 10) // Buffer for multiple workers
-	c.pendingMux.Lock()
-	c.pendingReqs[req.ID] = resultChan
-	c.pendingMux.Unlock()
+	opts := c.effectiveOptions(req.Options)
+	if err := c.validateOptions(opts); err != nil {
+		return models.OracleResult{
+			RequestID:       req.ID,
+			Query:           req.Query,
+			Timestamp:       time.Now(),
+			FinalValue:      0,
+			Strategy:        opts.Strategy,
+			WorkerResponses: []models.WorkerResult{},
+			ReliabilityNote: err.Error(),
+		}, err
+	}
+	req.Options = opts
+	target := c.quorumTarget(opts)
+	deadline := time.Now().Add(opts.Timeout())
 
-	// Clean up when done
-	defer func() {
-		c.pendingMux.Lock()
-		delete(c.pendingReqs, req.ID)
-		c.pendingMux.Unlock()
-		close(resultChan)
-	}()
+	pending := c.addPending(req, time.Now())
+	defer c.removePending(req.ID)
 
 	// Publish task to NATS
 	if err := c.PublishTask(req); err != nil {
 		return models.OracleResult{
 			RequestID:       req.ID,
+			Query:           req.Query,
+			Timestamp:       time.Now(),
 			FinalValue:      0,
+			Strategy:        opts.Strategy,
 			WorkerResponses: []models.WorkerResult{},
 			ReliabilityNote: fmt.Sprintf("Failed to publish task: %v", err),
+		}, err
+	}
+
+	c.requests.SetStatus(req.ID, models.RequestStatusDispatched)
+
+	// Replicate the request so another coordinator can finish it if this one dies
+	replicated := false
+	if c.shared != nil {
+		if err := c.shared.Create(req, deadline); err != nil {
+			log.Printf("❌ Failed to replicate request %s: %v", req.ID, err)
+		} else {
+			replicated = true
+			c.markReplicated(req.ID)
 		}
 	}
 
-	// Collect results with timeout
-	var workerResults []models.WorkerResult
-	timeout := time.After(5 * time.Second)
+	timeoutCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	result, err := c.collectResults(timeoutCtx, req, opts, target, pending, nil, replicated)
+	if replicated && !errors.Is(err, ErrLeaseLost) {
+		c.sharedUpdate(req.ID, c.shared.Finish(req.ID, result, err))
+	}
+	return result, err
+}
+
+const (
+	// minResultBuffer is the smallest result buffer allocated for a request
+	minResultBuffer = 10
+	// maxClockSkew is how far a worker's clock may lag behind the coordinator's
+	maxClockSkew = time.Minute
+)
+
+// pendingRequest routes worker results to the goroutine collecting a request
+type pendingRequest struct {
+	results chan models.WorkerResult
+	// done is closed once the request stops collecting results
+	done chan struct{}
+	// lost is closed once another coordinator takes over the request
+	lost     chan struct{}
+	lostOnce sync.Once
+	// replicated is set once the request is in the shared state; guarded by pendingMux
+	replicated bool
+	// query and since identify results produced for this request
+	query string
+	since time.Time
+}
+
+// accepts reports whether a result answers this request's query and was produced
+// after the request was dispatched, allowing for clock skew. Both fields are
+// signed by the worker, so a result for another query cannot be replayed under
+// a reused request ID.
+func (p *pendingRequest) accepts(result models.WorkerResult) bool {
+	return result.Query == p.query && !result.Timestamp.Before(p.since.Add(-maxClockSkew))
+}
+
+// addPending registers a channel that receives worker results for a request
+// dispatched at since. The buffer holds one result from every registered worker,
+// so a slow collector does not hold up the results subscription.
+func (c *Coordinator) addPending(req models.OracleRequest, since time.Time) *pendingRequest {
+	size := len(c.registry.List())
+	if size < minResultBuffer {
+		size = minResultBuffer
+	}
+
+	pending := &pendingRequest{
+		results: make(chan models.WorkerResult, size),
+		done:    make(chan struct{}),
+		lost:    make(chan struct{}),
+		query:   req.Query,
+		since:   since,
+	}
+	c.pendingMux.Lock()
+	c.pendingReqs[req.ID] = pending
+	c.pendingMux.Unlock()
+	return pending
+}
+
+// markReplicated records that a pending request is held in the shared state
+func (c *Coordinator) markReplicated(id string) {
+	c.pendingMux.Lock()
+	defer c.pendingMux.Unlock()
+
+	if pending, exists := c.pendingReqs[id]; exists {
+		pending.replicated = true
+	}
+}
+
+// markLost stops a replicated request that another coordinator has taken over.
+// Requests that were never replicated are left alone: their ID may belong to
+// another replica's request.
+func (c *Coordinator) markLost(id string) {
+	c.pendingMux.RLock()
+	pending, exists := c.pendingReqs[id]
+	replicated := exists && pending.replicated
+	c.pendingMux.RUnlock()
+
+	if replicated {
+		pending.lostOnce.Do(func() { close(pending.lost) })
+	}
+}
+
+// removePending stops routing results to a request. The results channel is left open
+// so a late send from handleWorkerResult can never panic; it is garbage collected
+// with the map entry. Results still buffered were never collected, so they are
+// recorded as late.
+func (c *Coordinator) removePending(id string) {
+	c.pendingMux.Lock()
+	pending, exists := c.pendingReqs[id]
+	delete(c.pendingReqs, id)
+	c.pendingMux.Unlock()
+
+	if !exists {
+		return
+	}
+	close(pending.done)
 
 	for {
 		select {
-		case result, ok := <-resultChan:
-			if !ok {
-				// Channel closed, return what we have
-				return c.aggregateResults(req.ID, workerResults)
+		case result := <-pending.results:
+			c.recordLate(result)
+		default:
+			return
+		}
+	}
+}
+
+// collectResults gathers worker results, starting from results already collected,
+// until the quorum target is reached or ctx ends, then aggregates them. A worker
+// that answers twice is counted once. When replicated is set, each result is
+// written to the shared request state. Active workers that have not answered by the
+// ctx deadline are reported as timed out. If another coordinator takes over the request
+// collection stops without aggregating and ErrLeaseLost is returned, so only the
+// new owner publishes a result.
+func (c *Coordinator) collectResults(ctx context.Context, req models.OracleRequest, opts models.RequestOptions, target int,
+	pending *pendingRequest, results []models.WorkerResult, replicated bool) (models.OracleResult, error) {
+	expected := c.registry.ActiveIDs()
+	seen := make(map[string]bool, len(results))
+	// answered counts the results that count toward the quorum. Saturated workers
+	// skipped the task, so their replies are reported but never complete a request.
+	answered := 0
+	for _, result := range results {
+		seen[result.WorkerID] = true
+		if !result.Saturated {
+			answered++
+		}
+	}
+
+	lost := func() (models.OracleResult, error) {
+		log.Printf("🔀 Request %s was taken over by another coordinator, stopping", req.ID)
+		return models.OracleResult{
+			RequestID:       req.ID,
+			Query:           req.Query,
+			Timestamp:       time.Now(),
+			Strategy:        opts.Strategy,
+			WorkerResponses: results,
+			ReliabilityNote: ErrLeaseLost.Error(),
+		}, ErrLeaseLost
+	}
+
+	complete := func(timedOut []string) (models.OracleResult, error) {
+		if replicated {
+			err := c.shared.SetStatus(req.ID, models.RequestStatusAggregating)
+			if errors.Is(err, ErrLeaseLost) {
+				return lost()
+			}
+			c.sharedUpdate(req.ID, err)
+		}
+		return c.completeRequest(req, results, timedOut, opts)
+	}
+
+	// missing lists the expected workers that have not answered
+	missing := func() []string {
+		var ids []string
+		for _, id := range expected {
+			if !seen[id] {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+
+	for {
+		if target > 0 && answered >= target {
+			log.Printf("✅ %d of %d expected responses received for request %s", answered, target, req.ID)
+			return complete(nil)
+		}
+
+		select {
+		case result := <-pending.results:
+			if seen[result.WorkerID] {
+				continue
+			}
+			seen[result.WorkerID] = true
+			results = append(results, result)
+			if !result.Saturated {
+				answered++
+			}
+			if replicated {
+				c.sharedUpdate(req.ID, c.shared.AddResult(req.ID, result))
 			}
-			workerResults = append(workerResults, result)
-		case <-timeout:
-			log.Printf("⏰ Timeout waiting for worker responses for request %s", req.ID)
-			return c.aggregateResults(req.ID, workerResults)
+			c.publishProgress(req, result, results)
 		case <-ctx.Done():
+			// Only a passed deadline is the workers' fault; a cancelled request is not
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				log.Printf("⏰ Timeout waiting for worker responses for request %s", req.ID)
+				return complete(missing())
+			}
 			log.Printf("❌ Context cancelled for request %s", req.ID)
-			return c.aggregateResults(req.ID, workerResults)
+			return complete(nil)
+		case <-c.stopping:
+			log.Printf("🛑 Coordinator stopping, aggregating %d responses for request %s", len(results), req.ID)
+			return complete(nil)
+		case <-pending.lost:
+			return lost()
 		}
 	}
 }
 
-// aggregateResults aggregates worker results and returns final result
-func (c *Coordinator) aggregateResults(requestID string, results []models.WorkerResult) models.OracleResult {
-	// Aggregate results using average
-	finalValue := AggregateResults(results, "average")
+// publishProgress streams a newly received worker result and the running aggregate
+func (c *Coordinator) publishProgress(req models.OracleRequest, result models.WorkerResult, results []models.WorkerResult) {
+	c.requests.Publish(req.ID, models.RequestEvent{Type: models.EventWorkerResult, Data: result})
+
+	value, err := c.aggregators.Aggregate(results, req.Options.Strategy)
+	if err != nil {
+		return
+	}
+	c.requests.Publish(req.ID, models.RequestEvent{
+		Type: models.EventProvisional,
+		Data: models.ProvisionalResult{
+			RequestID: req.ID,
+			Value:     value,
+			Strategy:  req.Options.Strategy,
+			Responses: len(results),
+		},
+	})
+}
+
+// completeRequest aggregates the collected results and enforces the min_responses floor
+func (c *Coordinator) completeRequest(req models.OracleRequest, results []models.WorkerResult, timedOut []string,
+	opts models.RequestOptions) (models.OracleResult, error) {
+	c.requests.SetStatus(req.ID, models.RequestStatusAggregating)
+
+	result, err := c.aggregateResults(req, results, timedOut)
+	if err != nil {
+		return result, err
+	}
+	if err := checkMinResponses(results, opts); err != nil {
+		return result, err
+	}
+
+	// Only values that were actually served belong in the history
+	if len(successfulValues(results)) > 0 {
+		c.recordHistory(result)
+	}
+	return result, nil
+}
+
+// aggregateResults aggregates worker results and returns final result. timedOut
+// lists the registered workers that did not respond before the deadline. An error
+// means no final value could be computed, e.g. for an unknown strategy.
+func (c *Coordinator) aggregateResults(req models.OracleRequest, results []models.WorkerResult, timedOut []string) (models.OracleResult, error) {
+	// Aggregate results using the requested strategy
+	strategy := req.Options.Strategy
+	finalValue, err := c.aggregators.Aggregate(results, strategy)
+	if err != nil {
+		log.Printf("❌ Aggregation failed for request %s: %v", req.ID, err)
+		return models.OracleResult{
+			RequestID:       req.ID,
+			Query:           req.Query,
+			Timestamp:       time.Now(),
+			Strategy:        strategy,
+			WorkerResponses: results,
+			TimedOutWorkers: timedOut,
+			ReliabilityNote: fmt.Sprintf("Aggregation failed: %v", err),
+		}, err
+	}
+
+	// Update per-worker reliability against the consensus value
+	c.stats.Record(results, finalValue)
+	c.stats.RecordTimeouts(timedOut)
 
 	// Calculate reliability note
-	reliabilityNote := c.calculateReliabilityNote(results)
+	reliabilityNote := c.calculateReliabilityNote(results, timedOut)
 
 	result := models.OracleResult{
-		RequestID:       requestID,
+		RequestID:       req.ID,
+		Query:           req.Query,
+		Timestamp:       time.Now(),
 		FinalValue:      finalValue,
+		Strategy:        strategy,
 		WorkerResponses: results,
+		TimedOutWorkers: timedOut,
 		ReliabilityNote: reliabilityNote,
 	}
 
+	c.signReport(&result)
 	utils.LogOracleResult(result)
-	return result
+	return result, nil
 }
 
-// calculateReliabilityNote calculates a reliability note based on worker responses
-func (c *Coordinator) calculateReliabilityNote(results []models.WorkerResult) string {
+// calculateReliabilityNote calculates a reliability note based on worker responses.
+// Workers that timed out count against the success rate.
+func (c *Coordinator) calculateReliabilityNote(results []models.WorkerResult, timedOut []string) string {
 	if len(results) == 0 {
+		if len(timedOut) > 0 {
+			return fmt.Sprintf("No workers responded: %d timed out", len(timedOut))
+		}
 		return "No workers responded"
 	}
 
@@ -174,25 +780,34 @@ func (c *Coordinator) calculateReliabilityNote(results []models.WorkerResult) st
 		}
 	}
 
-	successRate := float64(successCount) / float64(len(results))
+	total := len(results) + len(timedOut)
+	successRate := float64(successCount) / float64(total)
 
-	if successRate >= 0.8 {
+	var note string
+	if successRate >= 0.8 && len(timedOut) == 0 {
 		return "All workers responded successfully"
 	} else if successRate >= 0.5 {
-		return fmt.Sprintf("Partial response: %d/%d workers succeeded", successCount, len(results))
+		note = fmt.Sprintf("Partial response: %d/%d workers succeeded", successCount, total)
 	} else {
-		return fmt.Sprintf("Low reliability: only %d/%d workers succeeded", successCount, len(results))
+		note = fmt.Sprintf("Low reliability: only %d/%d workers succeeded", successCount, total)
 	}
+	if len(timedOut) > 0 {
+		note += fmt.Sprintf(", %d timed out", len(timedOut))
+	}
+	return note
 }
 
 // StartHTTPServer starts the coordinator HTTP server with middleware
 func (c *Coordinator) StartHTTPServer() {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
+	// Match on the escaped path so feed queries such as BTC%2FUSD stay a single segment
+	r.UseRawPath = true
 
 	// Initialize middleware
-	rateLimiter := NewRateLimitMiddleware(10.0, 20) // 10 requests/sec, burst of 20
+	rateLimiter := NewRateLimitMiddleware(c.rateLimit, c.rateBurst)
 	rateLimiter.StartCleanup()
+	c.requests.StartCleanup()
 
 	// Apply middleware
 	r.Use(gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
@@ -214,20 +829,45 @@ func (c *Coordinator) StartHTTPServer() {
 
 	// Register routes
 	r.GET("/health", c.handleHealth)
+	r.GET("/metrics", c.handleMetrics)
 	r.POST("/request", c.handleRequest)
+	r.POST("/requests", c.handleSubmitAsync)
+	r.GET("/requests/:id", c.handleGetRequest)
+	r.GET("/requests/:id/stream", c.handleStreamRequest)
+	r.POST("/feeds", c.handleCreateFeed)
+	r.GET("/feeds", c.handleListFeeds)
+	r.GET("/feeds/:query", c.handleGetFeed)
+	r.DELETE("/feeds/:query", c.handleDeleteFeed)
+	r.GET("/feeds/:query/history", c.handleFeedHistory)
+	r.GET("/aggregators", c.handleListAggregators)
+	r.GET("/workers", c.handleListWorkers)
+	r.GET("/keys", c.handleKeys)
+	r.GET("/workers/:id/stats", c.handleWorkerStats)
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", c.port),
+		Handler: r,
+	}
+	c.shutdownMux.Lock()
+	if c.shuttingDown {
+		c.shutdownMux.Unlock()
+		return
+	}
+	c.server = server
+	c.shutdownMux.Unlock()
 
-	log.Printf("🌐 Coordinator server starting on port %d with rate limiting (10 req/sec)", c.port)
-	if err := r.Run(fmt.Sprintf(":%d", c.port)); err != nil {
+	log.Printf("🌐 Coordinator server starting on port %d with rate limiting (%g req/sec, burst %d)", c.port, c.rateLimit, c.rateBurst)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Failed to start coordinator server: %v", err)
 	}
 }
 
-// handleRequest handles oracle request submissions
-func (c *Coordinator) handleRequest(ctx *gin.Context) {
+// bindRequest decodes and validates an oracle request, writing an error response on failure
+func (c *Coordinator) bindRequest(ctx *gin.Context) (models.OracleRequest, bool) {
 	var req models.OracleRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		WriteJSONError(ctx.Writer, "invalid request", 400, err.Error())
-		return
+		return req, false
 	}
 
 	// Generate request ID if not provided
@@ -235,11 +875,51 @@ func (c *Coordinator) handleRequest(ctx *gin.Context) {
 		req.ID = utils.GenerateRequestID()
 	}
 
-	// Process request with timeout
-	requestCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// Validate the options as they will be applied, so a client value that conflicts
+	// with a server default (e.g. max_responses below min_responses) is rejected here
+	if err := c.validateOptions(c.effectiveOptions(req.Options)); err != nil {
+		WriteJSONError(ctx.Writer, ErrInvalidRequest.Error, ErrInvalidRequest.Code, err.Error())
+		return req, false
+	}
+
+	return req, true
+}
+
+// handleRequest handles oracle request submissions
+func (c *Coordinator) handleRequest(ctx *gin.Context) {
+	req, ok := c.bindRequest(ctx)
+	if !ok {
+		return
+	}
+
+	// Process request with a timeout derived from the collection window
+	opts := c.effectiveOptions(req.Options)
+	requestCtx, cancel := context.WithTimeout(context.Background(), opts.Timeout()+handlerTimeoutMargin)
 	defer cancel()
 
-	result := c.SubmitRequest(requestCtx, req)
+	result, err := c.SubmitRequest(requestCtx, req)
+
+	if errors.Is(err, ErrCoordinatorStopping) {
+		WriteJSONError(ctx.Writer, ErrShuttingDown.Error, ErrShuttingDown.Code, err.Error())
+		return
+	}
+
+	if errors.Is(err, ErrInvalidOptions) {
+		WriteJSONError(ctx.Writer, ErrInvalidRequest.Error, ErrInvalidRequest.Code, err.Error())
+		return
+	}
+
+	if errors.Is(err, ErrDuplicateRequest) {
+		WriteJSONError(ctx.Writer, "duplicate request", http.StatusConflict,
+			fmt.Sprintf("request %s already exists", req.ID))
+		return
+	}
+
+	if errors.Is(err, ErrLeaseLost) {
+		WriteJSONError(ctx.Writer, "request moved", http.StatusServiceUnavailable,
+			fmt.Sprintf("request %s was taken over by another coordinator; poll GET /requests/%s for the result", req.ID, req.ID))
+		return
+	}
 
 	// Check if we got any results
 	if len(result.WorkerResponses) == 0 {
@@ -247,20 +927,75 @@ func (c *Coordinator) handleRequest(ctx *gin.Context) {
 		return
 	}
 
+	if errors.Is(err, ErrInsufficientResponses) {
+		WriteJSONError(ctx.Writer, ErrWorkerTimeout.Error, ErrWorkerTimeout.Code, err.Error())
+		return
+	}
+
 	ctx.JSON(http.StatusOK, result)
 }
 
+// handleListAggregators lists the aggregation strategies available on this coordinator
+func (c *Coordinator) handleListAggregators(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"strategies": c.aggregators.Names(),
+		"default":    c.defaults.Strategy,
+	})
+}
+
+// handleListWorkers lists the workers currently in the registry
+func (c *Coordinator) handleListWorkers(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"workers": c.registry.List(),
+		"active":  c.registry.ActiveCount(),
+	})
+}
+
+// handleWorkerStats returns the reliability stats recorded for a worker
+func (c *Coordinator) handleWorkerStats(ctx *gin.Context) {
+	id := ctx.Param("id")
+	stats, exists := c.stats.Get(id)
+	if !exists {
+		WriteJSONError(ctx.Writer, "worker not found", http.StatusNotFound,
+			fmt.Sprintf("no stats recorded for worker %s", id))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
 // handleHealth handles health check requests
 func (c *Coordinator) handleHealth(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{
-		"status": "healthy",
-		"port":   c.port,
-		"nats":   c.nc.IsConnected(),
+		"status":        "healthy",
+		"coordinator":   c.id,
+		"port":          c.port,
+		"nats":          c.nc.IsConnected(),
+		"nats_security": c.natsSecurity(),
+		"task_delivery": c.taskDelivery(),
+		"workers":       c.registry.ActiveCount(),
 	})
 }
 
+// natsSecurity describes how the NATS connection is authenticated and whether it uses TLS
+func (c *Coordinator) natsSecurity() gin.H {
+	auth := c.natsAuth
+	if auth == "" {
+		auth = "none"
+	}
+
+	security := gin.H{"auth": auth, "tls": false}
+	if state, err := c.nc.TLSConnectionState(); err == nil {
+		security["tls"] = true
+		security["tls_version"] = tls.VersionName(state.Version)
+		security["client_cert"] = c.natsMTLS
+	}
+	return security
+}
+
 // Close cleans up NATS connection
 func (c *Coordinator) Close() error {
+	c.feeds.StopAll()
 	if c.resultsSub != nil {
 		c.resultsSub.Unsubscribe()
 	}
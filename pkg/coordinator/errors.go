@@ -80,6 +80,12 @@ var (
 		Details: "an unexpected error occurred while processing the request",
 	}
 
+	ErrShuttingDown = &APIError{
+		Error:   "service shutting down",
+		Code:    503,
+		Details: "the coordinator is shutting down and not accepting new requests",
+	}
+
 	ErrNATSConnection = &APIError{
 		Error:   "message queue unavailable",
 		Code:    503,
@@ -0,0 +1,349 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/store"
+	"distributed-worker-system/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedsBucket is the store bucket holding feed definitions
+const feedsBucket = "feeds"
+
+// minFeedInterval is the shortest polling interval a feed may use
+const minFeedInterval = time.Second
+
+// ErrInvalidFeed is returned when a feed definition is malformed
+var ErrInvalidFeed = errors.New("invalid feed definition")
+
+// feed is a running feed and the function that stops it
+type feed struct {
+	state  models.FeedState
+	cancel context.CancelFunc
+}
+
+// FeedScheduler runs recurring price feeds and publishes values that moved
+// past their deviation threshold or whose heartbeat expired
+type FeedScheduler struct {
+	coordinator *Coordinator
+	feeds       map[string]*feed
+	mutex       sync.RWMutex
+	store       *store.Store
+}
+
+// NewFeedScheduler creates a scheduler that runs feeds through the coordinator
+func NewFeedScheduler(c *Coordinator) *FeedScheduler {
+	return &FeedScheduler{
+		coordinator: c,
+		feeds:       make(map[string]*feed),
+	}
+}
+
+// Persist saves feed definitions to st and starts every feed already stored there
+func (s *FeedScheduler) Persist(st *store.Store) error {
+	var defs []models.FeedDefinition
+	err := st.ForEach(feedsBucket, func(key string, data []byte) error {
+		var def models.FeedDefinition
+		if err := json.Unmarshal(data, &def); err != nil {
+			return fmt.Errorf("failed to decode feed %s: %v", key, err)
+		}
+		defs = append(defs, def)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.store = st
+	s.mutex.Unlock()
+
+	for _, def := range defs {
+		if err := s.Start(def); err != nil {
+			log.Printf("❌ Failed to restore feed %s: %v", def.Query, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a feed definition
+func (s *FeedScheduler) validate(def models.FeedDefinition) error {
+	if def.Query == "" {
+		return fmt.Errorf("%w: query is required", ErrInvalidFeed)
+	}
+	if def.Interval() < minFeedInterval {
+		return fmt.Errorf("%w: interval_ms must be at least %d", ErrInvalidFeed, minFeedInterval.Milliseconds())
+	}
+	if def.HeartbeatMs != 0 && def.Heartbeat() < def.Interval() {
+		return fmt.Errorf("%w: heartbeat_ms must not be shorter than interval_ms", ErrInvalidFeed)
+	}
+	if def.DeviationPercent < 0 {
+		return fmt.Errorf("%w: deviation_percent must not be negative", ErrInvalidFeed)
+	}
+	// Check the options each round will actually run with, defaults included
+	if err := s.coordinator.validateOptions(s.coordinator.effectiveOptions(def.Options)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+	}
+	return nil
+}
+
+// Start begins running a feed, replacing any existing feed for the same query
+func (s *FeedScheduler) Start(def models.FeedDefinition) error {
+	if err := s.validate(def); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if existing, exists := s.feeds[def.Query]; exists {
+		existing.cancel()
+	}
+
+	if s.store != nil {
+		if err := s.store.Put(feedsBucket, def.Query, def); err != nil {
+			return fmt.Errorf("failed to persist feed: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &feed{
+		state:  models.FeedState{FeedDefinition: def},
+		cancel: cancel,
+	}
+	s.feeds[def.Query] = f
+	go s.run(ctx, f)
+
+	log.Printf("📈 Feed %s started (every %s, deviation %.2f%%, heartbeat %s)",
+		def.Query, utils.FormatDuration(def.Interval()), def.DeviationPercent, utils.FormatDuration(def.Heartbeat()))
+	return nil
+}
+
+// Stop stops and removes the feed for query. It reports whether the feed existed.
+func (s *FeedScheduler) Stop(query string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	f, exists := s.feeds[query]
+	if !exists {
+		return false
+	}
+
+	f.cancel()
+	delete(s.feeds, query)
+	if s.store != nil {
+		if err := s.store.Delete(feedsBucket, query); err != nil {
+			log.Printf("❌ Failed to remove feed %s from store: %v", query, err)
+		}
+	}
+	log.Printf("📉 Feed %s stopped", query)
+	return true
+}
+
+// StopAll stops every running feed without removing persisted definitions
+func (s *FeedScheduler) StopAll() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, f := range s.feeds {
+		f.cancel()
+	}
+}
+
+// Get returns the current state of a feed
+func (s *FeedScheduler) Get(query string) (models.FeedState, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	f, exists := s.feeds[query]
+	if !exists {
+		return models.FeedState{}, false
+	}
+	return f.state, true
+}
+
+// List returns the state of every feed sorted by query
+func (s *FeedScheduler) List() []models.FeedState {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	states := make([]models.FeedState, 0, len(s.feeds))
+	for _, f := range s.feeds {
+		states = append(states, f.state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Query < states[j].Query })
+	return states
+}
+
+// run polls the workers at the feed's interval until ctx is cancelled
+func (s *FeedScheduler) run(ctx context.Context, f *feed) {
+	ticker := time.NewTicker(f.state.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.round(ctx, f)
+		}
+	}
+}
+
+// round runs a single feed request and publishes the value if needed
+func (s *FeedScheduler) round(ctx context.Context, f *feed) {
+	s.mutex.RLock()
+	def := f.state.FeedDefinition
+	s.mutex.RUnlock()
+
+	req := models.OracleRequest{
+		ID:      utils.GenerateRequestID(),
+		Query:   def.Query,
+		Options: def.Options,
+	}
+
+	opts := s.coordinator.effectiveOptions(req.Options)
+	roundCtx, cancel := context.WithTimeout(ctx, opts.Timeout()+handlerTimeoutMargin)
+	defer cancel()
+
+	result, err := s.coordinator.SubmitRequest(roundCtx, req)
+	if err == nil && len(result.WorkerResponses) == 0 {
+		err = fmt.Errorf("no workers responded")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	f.state.Rounds++
+	f.state.LastRoundAt = time.Now()
+	if err != nil {
+		f.state.LastError = err.Error()
+		log.Printf("⚠️  Feed %s round failed: %v", def.Query, err)
+		return
+	}
+	f.state.LastError = ""
+
+	reason := publishReason(f.state, result.FinalValue)
+	if reason == "" {
+		return
+	}
+
+	update := models.FeedUpdate{
+		Query:       def.Query,
+		Value:       result.FinalValue,
+		Reason:      reason,
+		Result:      result,
+		PublishedAt: time.Now(),
+	}
+	if err := s.coordinator.publishFeedUpdate(update); err != nil {
+		f.state.LastError = err.Error()
+		log.Printf("❌ Feed %s failed to publish: %v", def.Query, err)
+		return
+	}
+
+	f.state.LastValue = update.Value
+	f.state.LastPublishedAt = update.PublishedAt
+	f.state.LastResult = &result
+	f.state.Publications++
+}
+
+// publishReason decides whether a new value should be published and why.
+// It returns an empty string if the value should not be published.
+func publishReason(state models.FeedState, value float64) string {
+	if state.Publications == 0 {
+		return models.FeedReasonInitial
+	}
+
+	if state.LastValue != 0 {
+		deviation := math.Abs(value-state.LastValue) / math.Abs(state.LastValue) * 100
+		if deviation > state.DeviationPercent {
+			return models.FeedReasonDeviation
+		}
+	}
+
+	if state.HeartbeatMs > 0 && time.Since(state.LastPublishedAt) >= state.Heartbeat() {
+		return models.FeedReasonHeartbeat
+	}
+	return ""
+}
+
+// publishFeedUpdate publishes a feed value on the feed's NATS subject
+func (c *Coordinator) publishFeedUpdate(update models.FeedUpdate) error {
+	updateBytes, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feed update: %v", err)
+	}
+
+	subject := c.subjects.FeedPrefix + update.Query
+	if err := c.nc.Publish(subject, updateBytes); err != nil {
+		return fmt.Errorf("failed to publish feed update: %v", err)
+	}
+
+	log.Printf("📡 Feed %s published %.4f (%s)", update.Query, update.Value, update.Reason)
+	return nil
+}
+
+// handleCreateFeed creates or replaces a feed
+func (c *Coordinator) handleCreateFeed(ctx *gin.Context) {
+	var def models.FeedDefinition
+	if err := ctx.ShouldBindJSON(&def); err != nil {
+		WriteJSONError(ctx.Writer, "invalid request", 400, err.Error())
+		return
+	}
+
+	if err := c.feeds.Start(def); err != nil {
+		if errors.Is(err, ErrInvalidFeed) {
+			WriteJSONError(ctx.Writer, ErrInvalidRequest.Error, ErrInvalidRequest.Code, err.Error())
+			return
+		}
+		WriteJSONError(ctx.Writer, ErrInternalServer.Error, ErrInternalServer.Code, err.Error())
+		return
+	}
+
+	state, _ := c.feeds.Get(def.Query)
+	ctx.JSON(http.StatusCreated, state)
+}
+
+// handleListFeeds lists every feed with its latest state
+func (c *Coordinator) handleListFeeds(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"feeds": c.feeds.List(),
+	})
+}
+
+// handleGetFeed returns a single feed's state. Queries containing "/" must be URL-encoded.
+func (c *Coordinator) handleGetFeed(ctx *gin.Context) {
+	query := ctx.Param("query")
+	state, exists := c.feeds.Get(query)
+	if !exists {
+		WriteJSONError(ctx.Writer, "feed not found", http.StatusNotFound,
+			fmt.Sprintf("no feed defined for %s", query))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, state)
+}
+
+// handleDeleteFeed stops and removes a feed
+func (c *Coordinator) handleDeleteFeed(ctx *gin.Context) {
+	query := ctx.Param("query")
+	if !c.feeds.Stop(query) {
+		WriteJSONError(ctx.Writer, "feed not found", http.StatusNotFound,
+			fmt.Sprintf("no feed defined for %s", query))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,41 @@
+package coordinator
+
+import (
+	"errors"
+	"testing"
+
+	"distributed-worker-system/pkg/models"
+)
+
+func TestFeedValidateAppliesDefaults(t *testing.T) {
+	c := NewCoordinator(nil, 0)
+	if err := c.SetDefaultOptions(models.RequestOptions{MinResponses: 3}); err != nil {
+		t.Fatalf("SetDefaultOptions() error = %v", err)
+	}
+	scheduler := NewFeedScheduler(c)
+	interval := int(minFeedInterval.Milliseconds())
+
+	tests := []struct {
+		name    string
+		options models.RequestOptions
+		wantErr bool
+	}{
+		{"defaults only", models.RequestOptions{}, false},
+		{"max responses above the default minimum", models.RequestOptions{MaxResponses: 5}, false},
+		{"max responses below the default minimum", models.RequestOptions{MaxResponses: 2}, true},
+		{"own minimum within max responses", models.RequestOptions{MinResponses: 1, MaxResponses: 2}, false},
+		{"unknown strategy", models.RequestOptions{Strategy: "nonexistent"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := scheduler.validate(models.FeedDefinition{Query: "BTC/USD", IntervalMs: interval, Options: tt.options})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidFeed) {
+				t.Errorf("validate() error = %v, want ErrInvalidFeed", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// historyBucket is the store bucket holding aggregated results
+const historyBucket = "history"
+
+const (
+	// defaultHistoryWindow is the time range returned when no range is given
+	defaultHistoryWindow = 24 * time.Hour
+	// defaultHistoryLimit caps the number of results returned by a history query
+	defaultHistoryLimit = 1000
+)
+
+// ResultHistory persists aggregated results and serves time-range queries over them
+type ResultHistory struct {
+	store *store.Store
+}
+
+// NewResultHistory creates a history backed by st
+func NewResultHistory(st *store.Store) *ResultHistory {
+	return &ResultHistory{store: st}
+}
+
+// historyKey orders results by query and then by timestamp.
+// Timestamps are zero-padded so keys sort chronologically.
+func historyKey(query string, ts time.Time, requestID string) string {
+	return fmt.Sprintf("%s\x00%020d\x00%s", query, ts.UnixNano(), requestID)
+}
+
+// Record stores an aggregated result
+func (h *ResultHistory) Record(result models.OracleResult) error {
+	return h.store.Put(historyBucket, historyKey(result.Query, result.Timestamp, result.RequestID), result)
+}
+
+// Query returns results for query with timestamps between from and to, oldest first
+func (h *ResultHistory) Query(query string, from, to time.Time, limit int) ([]models.OracleResult, error) {
+	results := []models.OracleResult{}
+	var decodeErr error
+
+	// The upper bound sorts after every request ID recorded at the same instant
+	err := h.store.Range(historyBucket, historyKey(query, from, ""), historyKey(query, to, "\xff"),
+		func(key string, data []byte) bool {
+			var result models.OracleResult
+			if err := json.Unmarshal(data, &result); err != nil {
+				decodeErr = fmt.Errorf("failed to decode history entry: %v", err)
+				return false
+			}
+			results = append(results, result)
+			return len(results) < limit
+		})
+	if err != nil {
+		return nil, err
+	}
+	return results, decodeErr
+}
+
+// recordHistory persists a result if history is enabled
+func (c *Coordinator) recordHistory(result models.OracleResult) {
+	if c.history == nil {
+		return
+	}
+	if err := c.history.Record(result); err != nil {
+		log.Printf("❌ Failed to record history for request %s: %v", result.RequestID, err)
+	}
+}
+
+// parseHistoryTime accepts RFC 3339 timestamps or Unix seconds
+func parseHistoryTime(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// handleFeedHistory returns stored results for a query within a time range.
+// Queries containing "/" must be URL-encoded.
+func (c *Coordinator) handleFeedHistory(ctx *gin.Context) {
+	if c.history == nil {
+		WriteJSONError(ctx.Writer, "history unavailable", http.StatusServiceUnavailable,
+			"the coordinator was started without a data store")
+		return
+	}
+
+	query := ctx.Param("query")
+	now := time.Now()
+
+	to, err := parseHistoryTime(ctx.Query("to"), now)
+	if err != nil {
+		WriteJSONError(ctx.Writer, ErrInvalidRequest.Error, ErrInvalidRequest.Code, "to must be RFC 3339 or Unix seconds")
+		return
+	}
+	from, err := parseHistoryTime(ctx.Query("from"), to.Add(-defaultHistoryWindow))
+	if err != nil {
+		WriteJSONError(ctx.Writer, ErrInvalidRequest.Error, ErrInvalidRequest.Code, "from must be RFC 3339 or Unix seconds")
+		return
+	}
+	if from.After(to) {
+		WriteJSONError(ctx.Writer, ErrInvalidRequest.Error, ErrInvalidRequest.Code, "from must not be after to")
+		return
+	}
+
+	limit := defaultHistoryLimit
+	if value := ctx.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > defaultHistoryLimit {
+			WriteJSONError(ctx.Writer, ErrInvalidRequest.Error, ErrInvalidRequest.Code,
+				fmt.Sprintf("limit must be between 1 and %d", defaultHistoryLimit))
+			return
+		}
+	}
+
+	results, err := c.history.Query(query, from, to, limit)
+	if err != nil {
+		WriteJSONError(ctx.Writer, ErrInternalServer.Error, ErrInternalServer.Code, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"query":   query,
+		"from":    from,
+		"to":      to,
+		"results": results,
+	})
+}
@@ -0,0 +1,114 @@
+package coordinator
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/store"
+)
+
+// newTestHistory opens a history backed by a store in a temporary directory
+func newTestHistory(t *testing.T) *ResultHistory {
+	t.Helper()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("store.Open() error = %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return NewResultHistory(st)
+}
+
+func TestResultHistoryQuery(t *testing.T) {
+	history := newTestHistory(t)
+	base := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	records := []struct {
+		id     string
+		query  string
+		offset time.Duration
+	}{
+		{"req-1", "BTC/USD", 0},
+		{"req-2", "BTC/USD", time.Minute},
+		{"req-3", "BTC/USD", time.Minute},
+		{"req-4", "BTC/USD", 2 * time.Minute},
+		{"req-5", "BTC/USDT", time.Minute},
+		{"req-6", "ETH/USD", time.Minute},
+	}
+	for _, r := range records {
+		result := models.OracleResult{RequestID: r.id, Query: r.query, Timestamp: base.Add(r.offset)}
+		if err := history.Record(result); err != nil {
+			t.Fatalf("Record(%s) error = %v", r.id, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		from  time.Duration
+		to    time.Duration
+		limit int
+		want  []string
+	}{
+		{"whole range oldest first", "BTC/USD", 0, 2 * time.Minute, 10, []string{"req-1", "req-2", "req-3", "req-4"}},
+		{"bounds are inclusive", "BTC/USD", time.Minute, time.Minute, 10, []string{"req-2", "req-3"}},
+		{"limit", "BTC/USD", 0, 2 * time.Minute, 2, []string{"req-1", "req-2"}},
+		{"query prefix of another query", "BTC/USDT", 0, 2 * time.Minute, 10, []string{"req-5"}},
+		{"range before any result", "BTC/USD", -time.Hour, -time.Minute, 10, []string{}},
+		{"unknown query", "SOL/USD", 0, 2 * time.Minute, 10, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := history.Query(tt.query, base.Add(tt.from), base.Add(tt.to), tt.limit)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+
+			got := make([]string, len(results))
+			for i, result := range results {
+				got[i] = result.RequestID
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Query() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Query() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseHistoryTime(t *testing.T) {
+	fallback := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"empty uses fallback", "", fallback, false},
+		{"unix seconds", "1767323045", time.Unix(1767323045, 0), false},
+		{"rfc 3339", "2026-01-02T03:04:05Z", fallback, false},
+		{"rfc 3339 with offset", "2026-01-02T05:04:05+02:00", fallback, false},
+		{"date only", "2026-01-02", time.Time{}, true},
+		{"garbage", "yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHistoryTime(tt.value, fallback)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHistoryTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(tt.want) {
+				t.Errorf("parseHistoryTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
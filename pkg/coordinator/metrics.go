@@ -0,0 +1,47 @@
+package coordinator
+
+import (
+	"net/http"
+	"sync"
+
+	"distributed-worker-system/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResultMetrics counts worker results by what happened to them
+type ResultMetrics struct {
+	mutex    sync.Mutex
+	received uint64
+	dropped  uint64
+	late     uint64
+	unknown  uint64
+}
+
+// add increments one of the counters
+func (m *ResultMetrics) add(counter *uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	*counter++
+}
+
+// Snapshot returns the current counter values
+func (m *ResultMetrics) Snapshot() models.ResultMetrics {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return models.ResultMetrics{
+		Received: m.received,
+		Dropped:  m.dropped,
+		Late:     m.late,
+		Unknown:  m.unknown,
+	}
+}
+
+// handleMetrics returns the worker result counters
+func (c *Coordinator) handleMetrics(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"coordinator": c.id,
+		"results":     c.metrics.Snapshot(),
+	})
+}
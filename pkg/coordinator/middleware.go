@@ -10,6 +10,13 @@ import (
 	"golang.org/x/time/rate"
 )
 
+const (
+	// DefaultRateLimit is the default number of HTTP requests per second allowed per IP
+	DefaultRateLimit = 10.0
+	// DefaultRateBurst is the default burst size of the per-IP rate limit
+	DefaultRateBurst = 20
+)
+
 // RateLimiter represents a rate limiter for a specific IP address
 type RateLimiter struct {
 	limiter  *rate.Limiter
@@ -0,0 +1,151 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/utils"
+)
+
+var (
+	// ErrInsufficientResponses is returned when fewer than MinResponses workers succeeded
+	ErrInsufficientResponses = errors.New("not enough successful worker responses")
+	// ErrInvalidOptions is returned when request options are malformed or outside server limits
+	ErrInvalidOptions = errors.New("invalid request options")
+)
+
+// DefaultRequestOptions waits up to 5s for every registered worker and requires one successful response
+var DefaultRequestOptions = models.RequestOptions{
+	TimeoutMs:      5000,
+	Strategy:       DefaultStrategy,
+	QuorumFraction: 1.0,
+	MinResponses:   1,
+}
+
+// RequestLimits bounds the options clients may set on a request
+type RequestLimits struct {
+	MinTimeout   time.Duration `yaml:"min_timeout"`
+	MaxTimeout   time.Duration `yaml:"max_timeout"`
+	MaxResponses int           `yaml:"max_responses"`
+}
+
+// DefaultRequestLimits are the server-side limits applied to request options
+var DefaultRequestLimits = RequestLimits{
+	MinTimeout:   100 * time.Millisecond,
+	MaxTimeout:   30 * time.Second,
+	MaxResponses: 100,
+}
+
+// handlerTimeoutMargin is added to the collection timeout to bound the whole HTTP request
+const handlerTimeoutMargin = 5 * time.Second
+
+// SetDefaultOptions sets the options applied to requests that leave fields unset.
+// A zero timeout or empty strategy keeps the built-in default. The options are
+// validated against the coordinator's limits and strategies.
+func (c *Coordinator) SetDefaultOptions(opts models.RequestOptions) error {
+	if opts.TimeoutMs == 0 {
+		opts.TimeoutMs = DefaultRequestOptions.TimeoutMs
+	}
+	if opts.Strategy == "" {
+		opts.Strategy = DefaultRequestOptions.Strategy
+	}
+	if err := c.validateOptions(opts); err != nil {
+		return err
+	}
+	c.defaults = opts
+	return nil
+}
+
+// SetRequestLimits sets the server-side limits request options are validated against
+func (c *Coordinator) SetRequestLimits(limits RequestLimits) {
+	c.limits = limits
+}
+
+// effectiveOptions fills unset request options from the coordinator defaults
+func (c *Coordinator) effectiveOptions(opts models.RequestOptions) models.RequestOptions {
+	if opts.TimeoutMs == 0 {
+		opts.TimeoutMs = c.defaults.TimeoutMs
+	}
+	if opts.Strategy == "" {
+		opts.Strategy = c.defaults.Strategy
+	}
+	if opts.QuorumResponses == 0 && opts.QuorumFraction == 0 {
+		opts.QuorumResponses = c.defaults.QuorumResponses
+		opts.QuorumFraction = c.defaults.QuorumFraction
+	}
+	if opts.MinResponses == 0 {
+		opts.MinResponses = c.defaults.MinResponses
+	}
+	if opts.MaxResponses == 0 {
+		opts.MaxResponses = c.defaults.MaxResponses
+	}
+	return opts
+}
+
+// validateOptions checks request options against the coordinator's limits
+func (c *Coordinator) validateOptions(opts models.RequestOptions) error {
+	if opts.Strategy != "" {
+		if _, exists := c.aggregators.Get(opts.Strategy); !exists {
+			return fmt.Errorf("%w: unknown aggregation strategy %q (available: %s)",
+				ErrInvalidOptions, opts.Strategy, strings.Join(c.aggregators.Names(), ", "))
+		}
+	}
+
+	if opts.TimeoutMs < 0 {
+		return fmt.Errorf("%w: timeout_ms must not be negative", ErrInvalidOptions)
+	}
+	if timeout := opts.Timeout(); timeout != 0 && (timeout < c.limits.MinTimeout || timeout > c.limits.MaxTimeout) {
+		return fmt.Errorf("%w: timeout_ms must be between %s and %s",
+			ErrInvalidOptions, utils.FormatDuration(c.limits.MinTimeout), utils.FormatDuration(c.limits.MaxTimeout))
+	}
+
+	if opts.QuorumResponses < 0 || opts.MinResponses < 0 || opts.MaxResponses < 0 {
+		return fmt.Errorf("%w: response counts must not be negative", ErrInvalidOptions)
+	}
+	if opts.QuorumFraction < 0 || opts.QuorumFraction > 1 {
+		return fmt.Errorf("%w: quorum_fraction must be between 0 and 1", ErrInvalidOptions)
+	}
+	if opts.MaxResponses > c.limits.MaxResponses {
+		return fmt.Errorf("%w: max_responses must not exceed %d", ErrInvalidOptions, c.limits.MaxResponses)
+	}
+	if opts.MaxResponses > 0 && opts.MinResponses > opts.MaxResponses {
+		return fmt.Errorf("%w: min_responses must not exceed max_responses", ErrInvalidOptions)
+	}
+	return nil
+}
+
+// quorumTarget returns how many responses complete a request early.
+// Zero means wait for the full collection timeout.
+func (c *Coordinator) quorumTarget(opts models.RequestOptions) int {
+	target := 0
+	if opts.QuorumResponses > 0 {
+		target = opts.QuorumResponses
+	} else if registered := c.registry.ActiveCount(); opts.QuorumFraction > 0 && registered > 0 {
+		target = int(math.Max(1, math.Ceil(opts.QuorumFraction*float64(registered))))
+	}
+
+	// max_responses is a hard cap on collection
+	if opts.MaxResponses > 0 && (target == 0 || opts.MaxResponses < target) {
+		target = opts.MaxResponses
+	}
+	return target
+}
+
+// checkMinResponses returns ErrInsufficientResponses if too few workers succeeded
+func checkMinResponses(results []models.WorkerResult, opts models.RequestOptions) error {
+	successCount := 0
+	for _, result := range results {
+		if result.Err == "" {
+			successCount++
+		}
+	}
+
+	if successCount < opts.MinResponses {
+		return fmt.Errorf("%w: got %d, need %d", ErrInsufficientResponses, successCount, opts.MinResponses)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/signing"
+	"distributed-worker-system/pkg/store"
+)
+
+// keysBucket is the store bucket holding pinned worker public keys
+const keysBucket = "worker_keys"
+
+// ErrKeyMismatch is returned when a worker registers with a different public key than the one pinned for its ID
+var ErrKeyMismatch = errors.New("public key does not match the key pinned for this worker")
+
+// ErrDuplicateWorker is returned when a second process registers under the ID of a live worker
+var ErrDuplicateWorker = errors.New("worker id is already in use by another live process")
+
+// ErrUnauthenticated is returned when a control message for a worker is not signed by its pinned key
+var ErrUnauthenticated = errors.New("message is not signed by the key pinned for this worker")
+
+const (
+	// DefaultSuspectAfter is how long a worker may go without a heartbeat before it is marked suspect
+	DefaultSuspectAfter = 10 * time.Second
+	// DefaultEvictAfter is how long a worker may go without a heartbeat before it is evicted
+	DefaultEvictAfter = 15 * time.Second
+)
+
+// WorkerRegistry tracks live workers based on registrations and heartbeats
+type WorkerRegistry struct {
+	workers      map[string]*models.WorkerInfo
+	keys         map[string][]byte
+	mutex        sync.RWMutex
+	suspectAfter time.Duration
+	evictAfter   time.Duration
+	store        *store.Store
+}
+
+// NewWorkerRegistry creates a registry that evicts workers after evictAfter without a heartbeat
+func NewWorkerRegistry(suspectAfter, evictAfter time.Duration) *WorkerRegistry {
+	return &WorkerRegistry{
+		workers:      make(map[string]*models.WorkerInfo),
+		keys:         make(map[string][]byte),
+		suspectAfter: suspectAfter,
+		evictAfter:   evictAfter,
+	}
+}
+
+// Persist loads pinned worker keys from st and saves newly pinned keys to it
+func (r *WorkerRegistry) Persist(st *store.Store) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	err := st.ForEach(keysBucket, func(key string, data []byte) error {
+		var pub []byte
+		if err := json.Unmarshal(data, &pub); err != nil {
+			return fmt.Errorf("failed to decode key for %s: %v", key, err)
+		}
+		r.keys[key] = pub
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.store = st
+	return nil
+}
+
+// authenticate checks a control message from a worker that carries public key pub
+// and was sent at ts. verify checks the message signature against a key.
+// Once a key is pinned for an ID, every message must be signed by it and recent,
+// so nobody else can deregister the worker or take over its ID; messages without
+// a key or signature are rejected. The first signed message for an ID pins its key.
+// Workers that never sent a key are not authenticated. Callers must hold the mutex.
+func (r *WorkerRegistry) authenticate(id string, pub []byte, ts time.Time, verify func(pub ed25519.PublicKey) error) error {
+	pinned, exists := r.keys[id]
+	if exists {
+		if len(pub) > 0 && !bytes.Equal(pinned, pub) {
+			return ErrKeyMismatch
+		}
+		pub = pinned
+	}
+	if len(pub) == 0 {
+		return nil
+	}
+
+	if err := verify(pub); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	// Signed messages can be observed on the bus, so old ones must not be replayable
+	if age := time.Since(ts); age > maxClockSkew || age < -maxClockSkew {
+		return fmt.Errorf("%w: message sent at %s is outside the allowed clock skew",
+			ErrUnauthenticated, ts.Format(time.RFC3339))
+	}
+	if exists {
+		return nil
+	}
+
+	r.keys[id] = pub
+	if r.store != nil {
+		if err := r.store.Put(keysBucket, id, pub); err != nil {
+			log.Printf("❌ Failed to persist key for worker %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// PublicKey returns the public key pinned for a worker
+func (r *WorkerRegistry) PublicKey(id string) ([]byte, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	pub, exists := r.keys[id]
+	return pub, exists
+}
+
+// PublicKeys returns every pinned worker public key by worker ID
+func (r *WorkerRegistry) PublicKeys() map[string][]byte {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	keys := make(map[string][]byte, len(r.keys))
+	for id, pub := range r.keys {
+		keys[id] = pub
+	}
+	return keys
+}
+
+// Register adds a worker to the registry or refreshes an existing entry.
+// The first public key seen for an ID is pinned; later registrations must be
+// signed with it. While a worker is active, registrations from a different process
+// instance are rejected; once it turns suspect a new instance may take the ID over.
+func (r *WorkerRegistry) Register(req models.RegisterRequest) (models.WorkerInfo, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	err := r.authenticate(req.ID, req.PublicKey, req.Timestamp, func(pub ed25519.PublicKey) error {
+		return signing.VerifyRegister(pub, req)
+	})
+	if err != nil {
+		return models.WorkerInfo{}, err
+	}
+
+	info, err := r.admit(req.ID, req.InstanceID, req.Endpoint, req.MaxInFlight)
+	if err != nil {
+		return models.WorkerInfo{}, err
+	}
+	return *info, nil
+}
+
+// admit records an authenticated registration from a worker process instance.
+// Callers must hold the mutex.
+func (r *WorkerRegistry) admit(id, instanceID, endpoint string, maxInFlight int) (*models.WorkerInfo, error) {
+	now := time.Now()
+	info, exists := r.workers[id]
+	if exists && info.InstanceID != instanceID && info.Status == models.WorkerStatusActive &&
+		now.Sub(info.LastSeen) <= r.suspectAfter {
+		return nil, ErrDuplicateWorker
+	}
+	if !exists {
+		info = &models.WorkerInfo{
+			ID:           id,
+			RegisteredAt: now,
+		}
+		r.workers[id] = info
+	}
+	info.Endpoint = endpoint
+	info.InstanceID = instanceID
+	info.MaxInFlight = maxInFlight
+	info.PublicKey = r.keys[id]
+	info.LastSeen = now
+	info.Status = models.WorkerStatusActive
+	return info, nil
+}
+
+// Heartbeat records that a worker is alive. Unknown workers are registered,
+// so workers recover transparently after a coordinator restart. Heartbeats are
+// authenticated like registrations. It reports whether the worker was already known.
+func (r *WorkerRegistry) Heartbeat(hb models.Heartbeat) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, known := r.workers[hb.ID]
+	err := r.authenticate(hb.ID, hb.PublicKey, hb.Timestamp, func(pub ed25519.PublicKey) error {
+		return signing.VerifyHeartbeat(pub, hb)
+	})
+	if err != nil {
+		return known, err
+	}
+
+	info, err := r.admit(hb.ID, hb.InstanceID, hb.Endpoint, hb.MaxInFlight)
+	if err != nil {
+		return known, err
+	}
+	info.InFlight = hb.InFlight
+	return known, nil
+}
+
+// Deregister removes a worker from the registry. Only the process instance that
+// holds the registration may remove it, and a worker with a pinned key must sign
+// the deregistration. It reports whether the worker was removed.
+func (r *WorkerRegistry) Deregister(dereg models.Deregistration) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	err := r.authenticate(dereg.ID, nil, dereg.Timestamp, func(pub ed25519.PublicKey) error {
+		return signing.VerifyDeregister(pub, dereg)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	info, exists := r.workers[dereg.ID]
+	if !exists || info.InstanceID != dereg.InstanceID {
+		return false, nil
+	}
+	delete(r.workers, dereg.ID)
+	return true, nil
+}
+
+// Get returns the registry entry for a worker
+func (r *WorkerRegistry) Get(id string) (models.WorkerInfo, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	info, exists := r.workers[id]
+	if !exists {
+		return models.WorkerInfo{}, false
+	}
+	return *info, true
+}
+
+// List returns all registered workers sorted by ID
+func (r *WorkerRegistry) List() []models.WorkerInfo {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	workers := make([]models.WorkerInfo, 0, len(r.workers))
+	for _, info := range r.workers {
+		workers = append(workers, *info)
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].ID < workers[j].ID })
+	return workers
+}
+
+// ActiveCount returns the number of workers currently marked active
+func (r *WorkerRegistry) ActiveCount() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, info := range r.workers {
+		if info.Status == models.WorkerStatusActive {
+			count++
+		}
+	}
+	return count
+}
+
+// ActiveIDs returns the IDs of workers currently marked active, sorted
+func (r *WorkerRegistry) ActiveIDs() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ids := make([]string, 0, len(r.workers))
+	for id, info := range r.workers {
+		if info.Status == models.WorkerStatusActive {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sweep marks workers that missed heartbeats as suspect and evicts dead ones.
+// It returns the IDs of evicted workers.
+func (r *WorkerRegistry) sweep() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var evicted []string
+	now := time.Now()
+	for id, info := range r.workers {
+		silence := now.Sub(info.LastSeen)
+		switch {
+		case silence > r.evictAfter:
+			delete(r.workers, id)
+			evicted = append(evicted, id)
+		case silence > r.suspectAfter:
+			info.Status = models.WorkerStatusSuspect
+		}
+	}
+	return evicted
+}
+
+// StartEviction periodically sweeps the registry until ctx is cancelled
+func (r *WorkerRegistry) StartEviction(ctx context.Context, onEvict func(id string)) {
+	go func() {
+		ticker := time.NewTicker(r.suspectAfter / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, id := range r.sweep() {
+					onEvict(id)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,114 @@
+package coordinator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/signing"
+)
+
+// registryKey returns a deterministic key pair for the given seed byte
+func registryKey(b byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, ed25519.SeedSize))
+}
+
+// signedHeartbeat builds a heartbeat for id from instance, signed with signer and
+// carrying pub, sent at ts. A nil signer leaves it unsigned.
+func signedHeartbeat(id, instance string, pub []byte, signer ed25519.PrivateKey, ts time.Time) models.Heartbeat {
+	hb := models.Heartbeat{ID: id, InstanceID: instance, Endpoint: "host:8081", PublicKey: pub, Timestamp: ts}
+	if signer != nil {
+		signing.SignHeartbeat(signer, &hb)
+	}
+	return hb
+}
+
+// TestRegistryRejectsForgedControlMessages replays an attempt to silence a worker:
+// a forged deregistration followed by a heartbeat from a new instance.
+func TestRegistryRejectsForgedControlMessages(t *testing.T) {
+	victim, attacker := registryKey(1), registryKey(2)
+	victimPub := []byte(victim.Public().(ed25519.PublicKey))
+	attackerPub := []byte(attacker.Public().(ed25519.PublicKey))
+	id := signing.WorkerID(victimPub)
+
+	registry := NewWorkerRegistry(DefaultSuspectAfter, DefaultEvictAfter)
+	req := models.RegisterRequest{ID: id, InstanceID: "victim", Endpoint: "host:8081", PublicKey: victimPub,
+		Timestamp: time.Now()}
+	signing.SignRegister(victim, &req)
+	if _, err := registry.Register(req); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// The victim's instance ID is public, but a deregistration needs its key
+	forged := models.Deregistration{ID: id, InstanceID: "victim", Timestamp: time.Now()}
+	if removed, err := registry.Deregister(forged); removed || !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("unsigned Deregister() = %v, %v; want false, ErrUnauthenticated", removed, err)
+	}
+	signing.SignDeregister(attacker, &forged)
+	if removed, err := registry.Deregister(forged); removed || !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Deregister() signed by another key = %v, %v; want false, ErrUnauthenticated", removed, err)
+	}
+
+	// Once the worker is gone or suspect, no other instance may claim the ID
+	registry.workers[id].Status = models.WorkerStatusSuspect
+	registry.workers[id].LastSeen = time.Now().Add(-DefaultSuspectAfter - time.Second)
+
+	now := time.Now()
+	forgeries := []struct {
+		name    string
+		hb      models.Heartbeat
+		wantErr error
+	}{
+		{"no key", signedHeartbeat(id, "attacker", nil, nil, now), ErrUnauthenticated},
+		{"victim key unsigned", signedHeartbeat(id, "attacker", victimPub, nil, now), ErrUnauthenticated},
+		{"victim key signed by attacker", signedHeartbeat(id, "attacker", victimPub, attacker, now), ErrUnauthenticated},
+		{"attacker key", signedHeartbeat(id, "attacker", attackerPub, attacker, now), ErrKeyMismatch},
+		{"replayed victim heartbeat", signedHeartbeat(id, "attacker", victimPub, victim, now.Add(-time.Hour)), ErrUnauthenticated},
+	}
+	for _, tt := range forgeries {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := registry.Heartbeat(tt.hb); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Heartbeat() error = %v, want %v", err, tt.wantErr)
+			}
+			if info, _ := registry.Get(id); info.InstanceID != "victim" {
+				t.Errorf("instance holding the registration = %q, want victim", info.InstanceID)
+			}
+		})
+	}
+
+	// The real worker keeps its registration and can still leave
+	if _, err := registry.Heartbeat(signedHeartbeat(id, "victim", victimPub, victim, time.Now())); err != nil {
+		t.Fatalf("Heartbeat() from the real worker error = %v", err)
+	}
+	if info, _ := registry.Get(id); info.Status != models.WorkerStatusActive {
+		t.Errorf("worker status = %q, want active", info.Status)
+	}
+	dereg := models.Deregistration{ID: id, InstanceID: "victim", Timestamp: time.Now()}
+	signing.SignDeregister(victim, &dereg)
+	if removed, err := registry.Deregister(dereg); !removed || err != nil {
+		t.Errorf("Deregister() from the real worker = %v, %v; want true, nil", removed, err)
+	}
+}
+
+func TestRegistryUnkeyedWorkers(t *testing.T) {
+	registry := NewWorkerRegistry(DefaultSuspectAfter, DefaultEvictAfter)
+
+	if _, err := registry.Register(models.RegisterRequest{ID: "plain", InstanceID: "a"}); err != nil {
+		t.Fatalf("Register() without a key error = %v", err)
+	}
+	if _, err := registry.Heartbeat(models.Heartbeat{ID: "plain", InstanceID: "a"}); err != nil {
+		t.Fatalf("Heartbeat() without a key error = %v", err)
+	}
+	if _, err := registry.Heartbeat(models.Heartbeat{ID: "plain", InstanceID: "b"}); !errors.Is(err, ErrDuplicateWorker) {
+		t.Errorf("Heartbeat() from a second instance error = %v, want ErrDuplicateWorker", err)
+	}
+	if removed, err := registry.Deregister(models.Deregistration{ID: "plain", InstanceID: "b"}); removed || err != nil {
+		t.Errorf("Deregister() from another instance = %v, %v; want false, nil", removed, err)
+	}
+	if removed, err := registry.Deregister(models.Deregistration{ID: "plain", InstanceID: "a"}); !removed || err != nil {
+		t.Errorf("Deregister() = %v, %v; want true, nil", removed, err)
+	}
+}
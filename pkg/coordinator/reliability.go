@@ -0,0 +1,216 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/store"
+	"distributed-worker-system/pkg/utils"
+)
+
+// statsBucket is the store bucket holding per-worker reliability stats
+const statsBucket = "worker_stats"
+
+// deviationPenalty controls how strongly deviation from consensus lowers a worker's score.
+// A worker that is on average 10% away from consensus has its score halved.
+const deviationPenalty = 10.0
+
+// ReliabilityTracker records per-worker success, latency and deviation from consensus
+type ReliabilityTracker struct {
+	stats map[string]*models.WorkerStats
+	mutex sync.RWMutex
+	store *store.Store
+	// persistMux orders writes to the store so a stale snapshot never overwrites a newer one
+	persistMux sync.Mutex
+}
+
+// NewReliabilityTracker creates an in-memory reliability tracker
+func NewReliabilityTracker() *ReliabilityTracker {
+	return &ReliabilityTracker{
+		stats: make(map[string]*models.WorkerStats),
+	}
+}
+
+// Persist loads existing stats from st and writes every subsequent update back to it
+func (t *ReliabilityTracker) Persist(st *store.Store) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	err := st.ForEach(statsBucket, func(key string, data []byte) error {
+		var stats models.WorkerStats
+		if err := json.Unmarshal(data, &stats); err != nil {
+			return fmt.Errorf("failed to decode stats for %s: %v", key, err)
+		}
+		t.stats[key] = &stats
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	t.store = st
+	return nil
+}
+
+// Record updates stats for every worker that responded to a request.
+// consensus is the aggregated value the responses are compared against.
+func (t *ReliabilityTracker) Record(results []models.WorkerResult, consensus float64) {
+	ids := make([]string, 0, len(results))
+	defer func() { t.persist(ids) }()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, result := range results {
+		stats := t.statsFor(result.WorkerID)
+		ids = append(ids, result.WorkerID)
+
+		if result.Saturated {
+			// Capacity pressure says nothing about the worker's accuracy
+			stats.SaturatedCount++
+			stats.LastUpdated = time.Now()
+			continue
+		}
+
+		total := stats.SuccessCount + stats.FailureCount + 1
+		stats.AvgLatency += (result.ResponseTime - stats.AvgLatency) / time.Duration(latencySamples(stats)+1)
+
+		if result.Err == "" {
+			stats.SuccessCount++
+			deviation := 0.0
+			if consensus != 0 {
+				deviation = math.Abs(result.Value-consensus) / math.Abs(consensus)
+			}
+			stats.AvgDeviation += (deviation - stats.AvgDeviation) / float64(stats.SuccessCount)
+		} else {
+			stats.FailureCount++
+		}
+
+		stats.Score = score(stats)
+		stats.Reliable = utils.CalculateReliability(stats.SuccessCount, total)
+		stats.LastUpdated = time.Now()
+	}
+}
+
+// RecordTimeouts counts a failure for every registered worker that did not respond
+// to a request before its deadline
+func (t *ReliabilityTracker) RecordTimeouts(workerIDs []string) {
+	defer t.persist(workerIDs)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, id := range workerIDs {
+		stats := t.statsFor(id)
+		stats.TimeoutCount++
+		stats.FailureCount++
+		stats.Score = score(stats)
+		stats.Reliable = utils.CalculateReliability(stats.SuccessCount, stats.SuccessCount+stats.FailureCount)
+		stats.LastUpdated = time.Now()
+	}
+}
+
+// RecordLate folds a result that arrived after its request was aggregated into the
+// worker's latency. A missed deadline was already counted as a timeout, and a result
+// that only missed an early quorum is not a failure, so the success rate is unchanged.
+func (t *ReliabilityTracker) RecordLate(result models.WorkerResult) {
+	if result.Saturated {
+		return
+	}
+
+	defer t.persist([]string{result.WorkerID})
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.statsFor(result.WorkerID)
+	stats.AvgLatency += (result.ResponseTime - stats.AvgLatency) / time.Duration(latencySamples(stats)+1)
+	stats.LateCount++
+	stats.LastUpdated = time.Now()
+}
+
+// statsFor returns a worker's stats, creating them if needed. Callers must hold the mutex.
+func (t *ReliabilityTracker) statsFor(workerID string) *models.WorkerStats {
+	stats, exists := t.stats[workerID]
+	if !exists {
+		stats = &models.WorkerStats{WorkerID: workerID}
+		t.stats[workerID] = stats
+	}
+	return stats
+}
+
+// latencySamples returns how many responses AvgLatency averages over. Timeouts
+// carry no latency until their late result arrives.
+func latencySamples(stats *models.WorkerStats) int {
+	return stats.SuccessCount + stats.FailureCount - stats.TimeoutCount + stats.LateCount
+}
+
+// persist saves the current stats of the given workers to the store, if one is
+// attached, in a single transaction. It must be called without holding the mutex,
+// so lookups such as Score are not blocked by the disk write.
+func (t *ReliabilityTracker) persist(workerIDs []string) {
+	if len(workerIDs) == 0 {
+		return
+	}
+
+	t.persistMux.Lock()
+	defer t.persistMux.Unlock()
+
+	// Snapshot under the lock at write time, so the latest values always win
+	t.mutex.RLock()
+	st := t.store
+	snapshot := make(map[string]any, len(workerIDs))
+	for _, id := range workerIDs {
+		if stats, exists := t.stats[id]; exists {
+			copied := *stats
+			snapshot[id] = copied
+		}
+	}
+	t.mutex.RUnlock()
+
+	if st == nil {
+		return
+	}
+	if err := st.PutAll(statsBucket, snapshot); err != nil {
+		log.Printf("❌ Failed to persist stats for %d workers: %v", len(snapshot), err)
+	}
+}
+
+// score combines success rate and deviation from consensus into a value in [0, 1]
+func score(stats *models.WorkerStats) float64 {
+	total := stats.SuccessCount + stats.FailureCount
+	if total == 0 {
+		return 0
+	}
+
+	successRate := float64(stats.SuccessCount) / float64(total)
+	return successRate / (1 + deviationPenalty*stats.AvgDeviation)
+}
+
+// Get returns the stats for a worker
+func (t *ReliabilityTracker) Get(workerID string) (models.WorkerStats, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	stats, exists := t.stats[workerID]
+	if !exists {
+		return models.WorkerStats{}, false
+	}
+	return *stats, true
+}
+
+// Score returns a worker's reliability score, or ok=false if it has no history.
+// Saturated replies are not history: a worker that has only ever been saturated
+// has not succeeded or failed yet.
+func (t *ReliabilityTracker) Score(workerID string) (float64, bool) {
+	stats, exists := t.Get(workerID)
+	if !exists || stats.SuccessCount+stats.FailureCount == 0 {
+		return 0, false
+	}
+	return stats.Score, true
+}
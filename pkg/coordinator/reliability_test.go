@@ -0,0 +1,55 @@
+package coordinator
+
+import (
+	"math"
+	"testing"
+
+	"distributed-worker-system/pkg/models"
+)
+
+func TestReliabilityScore(t *testing.T) {
+	tracker := NewReliabilityTracker()
+	tracker.Record([]models.WorkerResult{
+		{WorkerID: "accurate", Value: 100},
+		{WorkerID: "failing", Err: "upstream error"},
+		{WorkerID: "saturated", Saturated: true},
+	}, 100)
+
+	tests := []struct {
+		worker    string
+		wantScore float64
+		wantKnown bool
+	}{
+		{"accurate", 1, true},
+		{"failing", 0, true},
+		{"saturated", 0, false},
+		{"unknown", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.worker, func(t *testing.T) {
+			got, known := tracker.Score(tt.worker)
+			if got != tt.wantScore || known != tt.wantKnown {
+				t.Errorf("Score(%s) = %v, %v; want %v, %v", tt.worker, got, known, tt.wantScore, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestReputationIgnoresSaturatedHistory(t *testing.T) {
+	tracker := NewReliabilityTracker()
+	tracker.Record([]models.WorkerResult{
+		{WorkerID: "accurate", Value: 100},
+		{WorkerID: "saturated", Saturated: true},
+	}, 100)
+
+	// The saturated worker has no accuracy history, so it gets the neutral weight
+	got := NewReputationAggregator(tracker).Aggregate([]models.WorkerResult{
+		{WorkerID: "accurate", Value: 100},
+		{WorkerID: "saturated", Value: 200},
+	})
+	want := (100*1 + 200*neutralWeight) / (1 + neutralWeight)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("Aggregate() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,320 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultResultRetention is how long finished requests stay queryable
+const DefaultResultRetention = 10 * time.Minute
+
+// subscriberBuffer is the number of events buffered for each stream subscriber
+const subscriberBuffer = 64
+
+// ErrDuplicateRequest is returned when a request ID is already in use
+var ErrDuplicateRequest = errors.New("request ID already in use")
+
+// RequestTracker records the lifecycle of oracle requests so they can be polled or streamed
+type RequestTracker struct {
+	requests    map[string]*models.RequestState
+	subscribers map[string][]chan models.RequestEvent
+	mutex       sync.RWMutex
+	retention   time.Duration
+}
+
+// NewRequestTracker creates a tracker that keeps finished requests for retention
+func NewRequestTracker(retention time.Duration) *RequestTracker {
+	return &RequestTracker{
+		requests:    make(map[string]*models.RequestState),
+		subscribers: make(map[string][]chan models.RequestEvent),
+		retention:   retention,
+	}
+}
+
+// Subscribe returns a channel of events for a request. The channel is closed after
+// the final event. Call the returned function to stop receiving events early.
+func (t *RequestTracker) Subscribe(id string) (<-chan models.RequestEvent, func(), bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	state, exists := t.requests[id]
+	if !exists {
+		return nil, nil, false
+	}
+
+	ch := make(chan models.RequestEvent, subscriberBuffer)
+	ch <- models.RequestEvent{Type: models.EventStatus, Data: *state}
+
+	// Finished requests get their final event straight away
+	if state.Result != nil {
+		ch <- models.RequestEvent{Type: models.EventFinal, Data: *state.Result}
+		close(ch)
+		return ch, func() {}, true
+	}
+
+	t.subscribers[id] = append(t.subscribers[id], ch)
+	unsubscribe := func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		t.removeSubscriber(id, ch)
+	}
+	return ch, unsubscribe, true
+}
+
+// removeSubscriber detaches and closes a subscriber channel. Callers must hold the mutex.
+func (t *RequestTracker) removeSubscriber(id string, ch chan models.RequestEvent) {
+	subs := t.subscribers[id]
+	for i, sub := range subs {
+		if sub == ch {
+			t.subscribers[id] = append(subs[:i], subs[i+1:]...)
+			close(ch)
+			break
+		}
+	}
+	if len(t.subscribers[id]) == 0 {
+		delete(t.subscribers, id)
+	}
+}
+
+// Publish sends an event to every subscriber of a request.
+// Slow subscribers miss events rather than blocking result collection.
+func (t *RequestTracker) Publish(id string, event models.RequestEvent) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	t.publishLocked(id, event)
+}
+
+// publishLocked sends an event to subscribers. Callers must hold the mutex.
+func (t *RequestTracker) publishLocked(id string, event models.RequestEvent) {
+	for _, ch := range t.subscribers[id] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Track starts tracking a request in the queued state.
+// It returns false if a request with the same ID is already tracked.
+func (t *RequestTracker) Track(req models.OracleRequest) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, exists := t.requests[req.ID]; exists {
+		return false
+	}
+
+	now := time.Now()
+	t.requests[req.ID] = &models.RequestState{
+		RequestID:   req.ID,
+		Query:       req.Query,
+		Status:      models.RequestStatusQueued,
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+	return true
+}
+
+// SetStatus moves a tracked request to a new state
+func (t *RequestTracker) SetStatus(id, status string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if state, exists := t.requests[id]; exists {
+		state.Status = status
+		state.UpdatedAt = time.Now()
+		t.publishLocked(id, models.RequestEvent{Type: models.EventStatus, Data: *state})
+	}
+}
+
+// Finish records the outcome of a request. A non-nil err marks it failed.
+func (t *RequestTracker) Finish(id string, result models.OracleResult, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	state, exists := t.requests[id]
+	if !exists {
+		return
+	}
+
+	state.Status = models.RequestStatusComplete
+	if err != nil {
+		state.Status = models.RequestStatusFailed
+		state.Error = err.Error()
+	}
+	state.Result = &result
+	state.UpdatedAt = time.Now()
+
+	// Deliver the final event and end every stream for this request. A subscriber
+	// that fell behind loses its oldest event instead of the final result. Events
+	// are only sent under the mutex, so the send cannot block once room is made.
+	final := models.RequestEvent{Type: models.EventFinal, Data: result}
+	for _, ch := range t.subscribers[id] {
+		select {
+		case ch <- final:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- final
+		}
+		close(ch)
+	}
+	delete(t.subscribers, id)
+}
+
+// Forget stops tracking a request and ends its streams without a final event
+func (t *RequestTracker) Forget(id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.requests, id)
+	for _, ch := range t.subscribers[id] {
+		close(ch)
+	}
+	delete(t.subscribers, id)
+}
+
+// Get returns the current state of a request
+func (t *RequestTracker) Get(id string) (models.RequestState, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	state, exists := t.requests[id]
+	if !exists {
+		return models.RequestState{}, false
+	}
+	return *state, true
+}
+
+// SetRetention changes how long finished requests are kept
+func (t *RequestTracker) SetRetention(retention time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.retention = retention
+}
+
+// cleanupFinished removes finished requests older than the retention period
+func (t *RequestTracker) cleanupFinished() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	for id, state := range t.requests {
+		finished := state.Status == models.RequestStatusComplete || state.Status == models.RequestStatusFailed
+		if finished && now.Sub(state.UpdatedAt) > t.retention {
+			delete(t.requests, id)
+		}
+	}
+}
+
+// StartCleanup starts the cleanup goroutine
+func (t *RequestTracker) StartCleanup() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			t.cleanupFinished()
+		}
+	}()
+}
+
+// SetResultRetention sets how long finished requests remain available at GET /requests/{id}
+func (c *Coordinator) SetResultRetention(retention time.Duration) {
+	c.requests.SetRetention(retention)
+}
+
+// handleSubmitAsync accepts an oracle request and processes it in the background
+func (c *Coordinator) handleSubmitAsync(ctx *gin.Context) {
+	req, ok := c.bindRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if c.isShuttingDown() {
+		WriteJSONError(ctx.Writer, ErrShuttingDown.Error, ErrShuttingDown.Code, ErrCoordinatorStopping.Error())
+		return
+	}
+
+	if !c.requests.Track(req) {
+		WriteJSONError(ctx.Writer, "duplicate request", http.StatusConflict,
+			fmt.Sprintf("request %s already exists", req.ID))
+		return
+	}
+
+	go func() {
+		opts := c.effectiveOptions(req.Options)
+		requestCtx, cancel := context.WithTimeout(context.Background(), opts.Timeout()+handlerTimeoutMargin)
+		defer cancel()
+
+		c.submitTracked(requestCtx, req)
+	}()
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"request_id": req.ID,
+		"status":     models.RequestStatusQueued,
+		"status_url": "/requests/" + req.ID,
+	})
+}
+
+// handleGetRequest returns the status of a request and its result once finished
+func (c *Coordinator) handleGetRequest(ctx *gin.Context) {
+	id := ctx.Param("id")
+	state, exists := c.requests.Get(id)
+	if !exists && c.shared != nil {
+		// The request may belong to another coordinator replica
+		shared, found, err := c.shared.Get(id)
+		if err != nil {
+			WriteJSONError(ctx.Writer, ErrInternalServer.Error, ErrInternalServer.Code, err.Error())
+			return
+		}
+		state, exists = toRequestState(shared), found
+	}
+	if !exists {
+		WriteJSONError(ctx.Writer, "request not found", http.StatusNotFound,
+			fmt.Sprintf("request %s is unknown or has expired", id))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, state)
+}
+
+// handleStreamRequest streams worker results, provisional aggregates and the final
+// result of a request as Server-Sent Events
+func (c *Coordinator) handleStreamRequest(ctx *gin.Context) {
+	id := ctx.Param("id")
+	events, unsubscribe, exists := c.requests.Subscribe(id)
+	if !exists {
+		WriteJSONError(ctx.Writer, "request not found", http.StatusNotFound,
+			fmt.Sprintf("request %s is unknown or has expired", id))
+		return
+	}
+	defer unsubscribe()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event.Data)
+			return event.Type != models.EventFinal
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
@@ -0,0 +1,449 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// DefaultStateBucket is the JetStream KV bucket holding shared request state
+	DefaultStateBucket = "oracle_requests"
+	// DefaultLeaseTTL is how long a coordinator owns a request without renewing its lease
+	DefaultLeaseTTL = 10 * time.Second
+	// maxUpdateAttempts bounds retries of a compare-and-set update that lost a race
+	maxUpdateAttempts = 5
+	// pendingPrefix prefixes the keys of requests that are still being collected
+	pendingPrefix = "pending."
+	// donePrefix prefixes the keys of finished requests
+	donePrefix = "done."
+)
+
+// ErrLeaseLost is returned when another replica has taken over a request
+var ErrLeaseLost = errors.New("request lease held by another coordinator")
+
+// SharedState keeps pending and finished request state in a JetStream KV bucket.
+// Each request is owned by one coordinator that renews a lease on it; when a lease
+// expires another replica claims the request and finishes it. Pending requests live
+// under their own key prefix and are mirrored locally by a watcher, so looking for
+// expired leases never scans finished requests.
+type SharedState struct {
+	kv       nats.KeyValue
+	owner    string
+	leaseTTL time.Duration
+
+	pendingMux sync.Mutex
+	// pending mirrors the pending keys with the revision last seen for each
+	pending map[string]pendingEntry
+}
+
+// pendingEntry is the last seen state of a pending request key
+type pendingEntry struct {
+	state    models.SharedRequest
+	revision uint64
+}
+
+// NewSharedState opens the KV bucket, creating it if needed. Entries expire after retention.
+func NewSharedState(nc *nats.Conn, bucket, owner string, leaseTTL, retention time.Duration) (*SharedState, error) {
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get JetStream context: %v", err)
+	}
+
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "Oracle coordinator request state",
+			History:     1,
+			TTL:         retention,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open KV bucket %s: %v", bucket, err)
+	}
+
+	return &SharedState{
+		kv:       kv,
+		owner:    owner,
+		leaseTTL: leaseTTL,
+		pending:  make(map[string]pendingEntry),
+	}, nil
+}
+
+// stateKey encodes a request ID as a valid KV key. Client-supplied IDs may contain
+// characters KV keys do not allow.
+func stateKey(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// pendingKey is the key of a request that is still being collected
+func pendingKey(id string) string {
+	return pendingPrefix + stateKey(id)
+}
+
+// doneKey is the key of a finished request
+func doneKey(id string) string {
+	return donePrefix + stateKey(id)
+}
+
+// Create records a newly dispatched request owned by this coordinator
+func (s *SharedState) Create(req models.OracleRequest, deadline time.Time) error {
+	now := time.Now()
+	data, err := json.Marshal(models.SharedRequest{
+		Request:      req,
+		Owner:        s.owner,
+		LeaseExpires: now.Add(s.leaseTTL),
+		Status:       models.RequestStatusDispatched,
+		Deadline:     deadline,
+		Results:      []models.WorkerResult{},
+		SubmittedAt:  now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request state: %v", err)
+	}
+
+	if _, err := s.kv.Create(pendingKey(req.ID), data); err != nil {
+		return fmt.Errorf("failed to store request state: %v", err)
+	}
+	return nil
+}
+
+// Get returns the shared state of a request, pending or finished
+func (s *SharedState) Get(id string) (models.SharedRequest, bool, error) {
+	state, _, err := s.get(pendingKey(id))
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		state, _, err = s.get(doneKey(id))
+	}
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return models.SharedRequest{}, false, nil
+	}
+	return state, err == nil, err
+}
+
+// get reads and decodes an entry along with its revision
+func (s *SharedState) get(key string) (models.SharedRequest, uint64, error) {
+	entry, err := s.kv.Get(key)
+	if err != nil {
+		return models.SharedRequest{}, 0, err
+	}
+
+	var state models.SharedRequest
+	if err := json.Unmarshal(entry.Value(), &state); err != nil {
+		return models.SharedRequest{}, 0, fmt.Errorf("failed to decode request state: %v", err)
+	}
+	return state, entry.Revision(), nil
+}
+
+// update applies fn to a request this coordinator owns and writes it back with a
+// compare-and-set, retrying when a concurrent writer got there first
+func (s *SharedState) update(id string, fn func(state *models.SharedRequest)) error {
+	key := pendingKey(id)
+
+	var err error
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var state models.SharedRequest
+		var revision uint64
+		state, revision, err = s.get(key)
+		if err != nil {
+			return err
+		}
+		if state.Owner != s.owner {
+			return ErrLeaseLost
+		}
+
+		fn(&state)
+		state.UpdatedAt = time.Now()
+
+		data, marshalErr := json.Marshal(state)
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal request state: %v", marshalErr)
+		}
+		if _, err = s.kv.Update(key, data, revision); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to update request state: %v", err)
+}
+
+// AddResult appends a worker result to an owned request
+func (s *SharedState) AddResult(id string, result models.WorkerResult) error {
+	return s.update(id, func(state *models.SharedRequest) {
+		state.Results = append(state.Results, result)
+	})
+}
+
+// SetStatus moves an owned request to a new state
+func (s *SharedState) SetStatus(id, status string) error {
+	return s.update(id, func(state *models.SharedRequest) {
+		state.Status = status
+	})
+}
+
+// Renew extends this coordinator's lease on a request
+func (s *SharedState) Renew(id string) error {
+	return s.update(id, func(state *models.SharedRequest) {
+		state.LeaseExpires = time.Now().Add(s.leaseTTL)
+	})
+}
+
+// Finish records the outcome of an owned request and moves it from the pending
+// to the finished keys. A non-nil err marks it failed.
+func (s *SharedState) Finish(id string, result models.OracleResult, err error) error {
+	key := pendingKey(id)
+	state, revision, getErr := s.get(key)
+	if getErr != nil {
+		return getErr
+	}
+	if state.Owner != s.owner {
+		return ErrLeaseLost
+	}
+
+	state.Status = models.RequestStatusComplete
+	if err != nil {
+		state.Status = models.RequestStatusFailed
+		state.Error = err.Error()
+	}
+	state.Result = &result
+	state.UpdatedAt = time.Now()
+
+	data, marshalErr := json.Marshal(state)
+	if marshalErr != nil {
+		return fmt.Errorf("failed to marshal request state: %v", marshalErr)
+	}
+	if _, err := s.kv.Put(doneKey(id), data); err != nil {
+		return fmt.Errorf("failed to store finished request state: %v", err)
+	}
+	if err := s.kv.Delete(key, nats.LastRevision(revision)); err != nil {
+		return fmt.Errorf("failed to remove pending request state: %v", err)
+	}
+	return nil
+}
+
+// watchPending mirrors the pending keys into s.pending until ctx is cancelled.
+// lost is called with the ID of every pending request another coordinator owns,
+// so the coordinator can stop collecting requests it no longer holds.
+func (s *SharedState) watchPending(ctx context.Context, lost func(id string)) error {
+	watcher, err := s.kv.Watch(pendingPrefix + ">")
+	if err != nil {
+		return fmt.Errorf("failed to watch pending requests: %v", err)
+	}
+
+	go func() {
+		defer watcher.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entry, ok := <-watcher.Updates():
+				if !ok {
+					return
+				}
+				// A nil entry marks the end of the initial values
+				if entry == nil {
+					continue
+				}
+				s.applyPending(entry, lost)
+			}
+		}
+	}()
+	return nil
+}
+
+// applyPending updates the local mirror from a watched pending key
+func (s *SharedState) applyPending(entry nats.KeyValueEntry, lost func(id string)) {
+	s.pendingMux.Lock()
+	defer s.pendingMux.Unlock()
+
+	if entry.Operation() != nats.KeyValuePut {
+		delete(s.pending, entry.Key())
+		return
+	}
+
+	var state models.SharedRequest
+	if err := json.Unmarshal(entry.Value(), &state); err != nil {
+		log.Printf("❌ Failed to decode pending request %s: %v", entry.Key(), err)
+		return
+	}
+	s.pending[entry.Key()] = pendingEntry{state: state, revision: entry.Revision()}
+	if state.Owner != s.owner {
+		lost(state.Request.ID)
+	}
+}
+
+// ClaimExpired takes over every unfinished request whose lease has expired and
+// returns the claimed requests. It only looks at the locally mirrored pending keys.
+func (s *SharedState) ClaimExpired() ([]models.SharedRequest, error) {
+	now := time.Now()
+	s.pendingMux.Lock()
+	expired := make(map[string]pendingEntry)
+	for key, entry := range s.pending {
+		if !isFinished(entry.state.Status) && entry.state.Owner != s.owner && !now.Before(entry.state.LeaseExpires) {
+			expired[key] = entry
+		}
+	}
+	s.pendingMux.Unlock()
+
+	var claimed []models.SharedRequest
+	for key, entry := range expired {
+		state, revision := entry.state, entry.revision
+
+		previous := state.Owner
+		state.Owner = s.owner
+		state.LeaseExpires = now.Add(s.leaseTTL)
+		state.UpdatedAt = now
+		data, err := json.Marshal(state)
+		if err != nil {
+			continue
+		}
+
+		// Another replica may claim the same request; only one compare-and-set wins
+		if _, err := s.kv.Update(key, data, revision); err != nil {
+			continue
+		}
+		log.Printf("🔁 Took over request %s from coordinator %s", state.Request.ID, previous)
+		claimed = append(claimed, state)
+	}
+	return claimed, nil
+}
+
+// isFinished reports whether a request status is terminal
+func isFinished(status string) bool {
+	return status == models.RequestStatusComplete || status == models.RequestStatusFailed
+}
+
+// toRequestState converts shared state to the status returned by GET /requests/{id}
+func toRequestState(state models.SharedRequest) models.RequestState {
+	return models.RequestState{
+		RequestID:   state.Request.ID,
+		Query:       state.Request.Query,
+		Status:      state.Status,
+		Error:       state.Error,
+		Result:      state.Result,
+		SubmittedAt: state.SubmittedAt,
+		UpdatedAt:   state.UpdatedAt,
+	}
+}
+
+// UseSharedState keeps request state in a JetStream KV bucket so replicas can take
+// over each other's requests. It must be called before SubscribeResults.
+func (c *Coordinator) UseSharedState(bucket string, leaseTTL time.Duration) error {
+	shared, err := NewSharedState(c.nc, bucket, c.id, leaseTTL, c.requests.retention)
+	if err != nil {
+		return err
+	}
+
+	c.shared = shared
+	log.Printf("🗄️  Sharing request state in KV bucket %s (lease %v)", bucket, leaseTTL)
+	return nil
+}
+
+// sharedUpdate logs failures of a shared state write; the request carries on locally
+func (c *Coordinator) sharedUpdate(id string, err error) {
+	if err == nil || errors.Is(err, nats.ErrKeyNotFound) {
+		// Requests that were never replicated have no shared state
+		return
+	}
+	if errors.Is(err, ErrLeaseLost) {
+		log.Printf("⚠️  Lost lease on request %s to another coordinator", id)
+		c.markLost(id)
+		return
+	}
+	log.Printf("❌ Failed to update shared state for request %s: %v", id, err)
+}
+
+// startFailover renews leases on the requests this coordinator is collecting and
+// takes over requests whose owner stopped renewing, until ctx is cancelled
+func (c *Coordinator) startFailover(ctx context.Context) error {
+	if err := c.shared.watchPending(ctx, c.markLost); err != nil {
+		return err
+	}
+
+	go func() {
+		renew := time.NewTicker(c.shared.leaseTTL / 3)
+		defer renew.Stop()
+		claim := time.NewTicker(c.shared.leaseTTL / 2)
+		defer claim.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-renew.C:
+				c.pendingMux.RLock()
+				ids := make([]string, 0, len(c.pendingReqs))
+				for id, pending := range c.pendingReqs {
+					if pending.replicated {
+						ids = append(ids, id)
+					}
+				}
+				c.pendingMux.RUnlock()
+
+				for _, id := range ids {
+					c.sharedUpdate(id, c.shared.Renew(id))
+				}
+			case <-claim.C:
+				if c.isShuttingDown() {
+					continue
+				}
+				claimed, err := c.shared.ClaimExpired()
+				if err != nil {
+					log.Printf("❌ Failed to check for orphaned requests: %v", err)
+					continue
+				}
+				for _, state := range claimed {
+					go c.resumeRequest(state)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// resumeRequest finishes a request taken over from another coordinator. Results the
+// previous owner collected are kept; if the collection window is still open the task
+// is published again so workers reply to this coordinator.
+func (c *Coordinator) resumeRequest(state models.SharedRequest) {
+	req := state.Request
+	if !c.beginRequest() {
+		return
+	}
+	defer c.inFlight.Done()
+
+	c.requests.Track(req)
+	c.requests.SetStatus(req.ID, models.RequestStatusDispatched)
+
+	pending := c.addPending(req, state.SubmittedAt)
+	c.markReplicated(req.ID)
+	defer c.removePending(req.ID)
+
+	opts := req.Options
+	target := c.quorumTarget(opts)
+	results := state.Results
+
+	if time.Now().Before(state.Deadline) && (target == 0 || len(results) < target) {
+		req.ReplyTo = ""
+		if err := c.PublishTask(req); err != nil {
+			log.Printf("❌ Failed to republish task %s: %v", req.ID, err)
+		}
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), state.Deadline)
+	defer cancel()
+
+	result, err := c.collectResults(ctx, req, opts, target, pending, results, true)
+	c.finishTracked(req.ID, result, err)
+	if !errors.Is(err, ErrLeaseLost) {
+		c.sharedUpdate(req.ID, c.shared.Finish(req.ID, result, err))
+	}
+}
@@ -0,0 +1,171 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+
+	"github.com/nats-io/nats.go"
+)
+
+// startNATS runs a local JetStream-enabled nats-server for the test and returns a
+// connection to it. The binary comes from $NATS_SERVER or the PATH; the test fails
+// without one unless SKIP_NATS_TESTS=1 opts out explicitly.
+func startNATS(t *testing.T) *nats.Conn {
+	t.Helper()
+
+	bin := os.Getenv("NATS_SERVER")
+	if bin == "" {
+		bin = "nats-server"
+	}
+	bin, err := exec.LookPath(bin)
+	if err != nil {
+		if os.Getenv("SKIP_NATS_TESTS") == "1" {
+			t.Skip("nats-server not found and SKIP_NATS_TESTS=1")
+		}
+		t.Fatalf("nats-server not found (run `make test` to install it, or set NATS_SERVER): %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	cmd := exec.Command(bin, "-js", "-a", "127.0.0.1", "-p", fmt.Sprint(port), "-sd", t.TempDir())
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start nats-server: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	url := fmt.Sprintf("nats://127.0.0.1:%d", port)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		nc, err := nats.Connect(url)
+		if err == nil {
+			t.Cleanup(nc.Close)
+			return nc
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("failed to connect to nats-server: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// eventually polls check until it succeeds or the timeout passes
+func eventually(t *testing.T, timeout time.Duration, what string, check func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !check() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestSharedStateTakeover(t *testing.T) {
+	nc := startNATS(t)
+	const leaseTTL = 200 * time.Millisecond
+
+	first, err := NewSharedState(nc, "TEST_REQUESTS", "coordinator-a", leaseTTL, time.Minute)
+	if err != nil {
+		t.Fatalf("NewSharedState: %v", err)
+	}
+	second, err := NewSharedState(nc, "TEST_REQUESTS", "coordinator-b", leaseTTL, time.Minute)
+	if err != nil {
+		t.Fatalf("NewSharedState: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lost := make(chan string, 10)
+	if err := first.watchPending(ctx, func(id string) { lost <- id }); err != nil {
+		t.Fatalf("watchPending: %v", err)
+	}
+	if err := second.watchPending(ctx, func(string) {}); err != nil {
+		t.Fatalf("watchPending: %v", err)
+	}
+
+	req := models.OracleRequest{ID: "req/1", Query: "BTC/USD"}
+	if err := first.Create(req, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	result := models.WorkerResult{WorkerID: "worker-1", RequestID: req.ID, Value: 42}
+	if err := first.AddResult(req.ID, result); err != nil {
+		t.Fatalf("AddResult: %v", err)
+	}
+
+	// The lease is live, so the request is not claimed yet
+	eventually(t, 2*time.Second, "the pending request to be mirrored", func() bool {
+		second.pendingMux.Lock()
+		defer second.pendingMux.Unlock()
+		return len(second.pending) == 1
+	})
+	claimed, err := second.ClaimExpired()
+	if err != nil {
+		t.Fatalf("ClaimExpired: %v", err)
+	}
+	if len(claimed) != 0 {
+		t.Fatalf("claimed %d requests with a live lease", len(claimed))
+	}
+
+	// Once the owner stops renewing, the other replica takes the request over
+	eventually(t, 5*time.Second, "the expired request to be claimed", func() bool {
+		claimed, err = second.ClaimExpired()
+		return err == nil && len(claimed) == 1
+	})
+	if claimed[0].Owner != "coordinator-b" {
+		t.Errorf("claimed owner = %q, want coordinator-b", claimed[0].Owner)
+	}
+	if len(claimed[0].Results) != 1 || claimed[0].Results[0].WorkerID != "worker-1" {
+		t.Errorf("claimed results = %+v, want the result collected by the first owner", claimed[0].Results)
+	}
+
+	// The previous owner is told it lost the request and can no longer write to it
+	select {
+	case id := <-lost:
+		if id != req.ID {
+			t.Errorf("lost request = %q, want %q", id, req.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("previous owner was not told it lost the request")
+	}
+	if err := first.Renew(req.ID); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Renew by previous owner = %v, want ErrLeaseLost", err)
+	}
+	if err := first.Finish(req.ID, models.OracleResult{RequestID: req.ID}, nil); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Finish by previous owner = %v, want ErrLeaseLost", err)
+	}
+
+	// The new owner finishes the request, which leaves the pending keys
+	if err := second.Finish(req.ID, models.OracleResult{RequestID: req.ID, FinalValue: 42}, nil); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	state, found, err := first.Get(req.ID)
+	if err != nil || !found {
+		t.Fatalf("Get = %v, %v; want the finished request", found, err)
+	}
+	if state.Status != models.RequestStatusComplete || state.Result == nil || state.Result.FinalValue != 42 {
+		t.Errorf("finished state = %+v, want complete with value 42", state)
+	}
+	eventually(t, 2*time.Second, "the finished request to leave the pending keys", func() bool {
+		second.pendingMux.Lock()
+		defer second.pendingMux.Unlock()
+		return len(second.pending) == 0
+	})
+}
@@ -0,0 +1,114 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultShutdownTimeout is how long Shutdown lets in-flight requests keep collecting results
+const DefaultShutdownTimeout = 10 * time.Second
+
+// drainPollInterval is how often Shutdown checks whether the NATS connection has drained
+const drainPollInterval = 50 * time.Millisecond
+
+// responseWriteTimeout is how long Shutdown waits for HTTP handlers to write the
+// responses of requests that were aggregated after the shutdown deadline
+const responseWriteTimeout = 5 * time.Second
+
+// ErrCoordinatorStopping is returned for requests submitted while the coordinator shuts down
+var ErrCoordinatorStopping = errors.New("coordinator is shutting down")
+
+// beginRequest registers an in-flight request. It returns false once shutdown has started.
+func (c *Coordinator) beginRequest() bool {
+	c.shutdownMux.Lock()
+	defer c.shutdownMux.Unlock()
+
+	if c.shuttingDown {
+		return false
+	}
+	c.inFlight.Add(1)
+	return true
+}
+
+// isShuttingDown reports whether Shutdown has been called
+func (c *Coordinator) isShuttingDown() bool {
+	c.shutdownMux.Lock()
+	defer c.shutdownMux.Unlock()
+
+	return c.shuttingDown
+}
+
+// Shutdown stops the coordinator gracefully. It stops accepting new requests and
+// feed rounds, and lets in-flight requests keep collecting results until ctx expires.
+// Requests still running after that are aggregated with the results they have, and
+// their handlers get up to responseWriteTimeout to write the responses. It then
+// unsubscribes from worker results and drains the NATS connection.
+func (c *Coordinator) Shutdown(ctx context.Context) error {
+	c.shutdownMux.Lock()
+	if c.shuttingDown {
+		c.shutdownMux.Unlock()
+		return nil
+	}
+	c.shuttingDown = true
+	server := c.server
+	c.shutdownMux.Unlock()
+
+	c.feeds.StopAll()
+
+	// Stop accepting connections; open handlers finish once their requests complete
+	serverErr := make(chan error, 1)
+	go func() {
+		if server == nil {
+			serverErr <- nil
+			return
+		}
+		serverErr <- server.Shutdown(ctx)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("✅ All in-flight requests completed")
+	case <-ctx.Done():
+		log.Printf("⏰ Shutdown deadline reached, aggregating in-flight requests with the results collected so far")
+		close(c.stopping)
+		<-done
+	}
+
+	err := <-serverErr
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		// The server stopped waiting when ctx expired, but handlers of the requests
+		// aggregated since then are still writing their responses
+		writeCtx, cancel := context.WithTimeout(context.Background(), responseWriteTimeout)
+		err = server.Shutdown(writeCtx)
+		cancel()
+	}
+	if err != nil {
+		log.Printf("❌ HTTP server shutdown error: %v", err)
+	}
+
+	if c.resultsSub != nil {
+		if err := c.resultsSub.Unsubscribe(); err != nil {
+			log.Printf("⚠️  Failed to unsubscribe from results: %v", err)
+		}
+	}
+
+	if c.nc == nil {
+		return nil
+	}
+	if err := c.nc.Drain(); err != nil {
+		return fmt.Errorf("failed to drain NATS connection: %v", err)
+	}
+	for !c.nc.IsClosed() {
+		time.Sleep(drainPollInterval)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package coordinator
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestShutdownWaitsForResponses checks that a synchronous request aggregated after
+// the shutdown deadline still gets its response before Shutdown returns
+func TestShutdownWaitsForResponses(t *testing.T) {
+	c := NewCoordinator(nil, 0)
+
+	started := make(chan struct{})
+	var written atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/request", func(w http.ResponseWriter, r *http.Request) {
+		if !c.beginRequest() {
+			http.Error(w, "stopping", http.StatusServiceUnavailable)
+			return
+		}
+		close(started)
+
+		// Like collectResults: the request is aggregated once shutdown stops it,
+		// and the handler writes the response after the request has finished
+		<-c.stopping
+		c.inFlight.Done()
+		time.Sleep(200 * time.Millisecond)
+		io.WriteString(w, "aggregated")
+		written.Store(true)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	c.server = &http.Server{Handler: mux}
+	go c.server.Serve(listener)
+
+	type response struct {
+		body string
+		err  error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/request")
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- response{body: string(body), err: err}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := c.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if !written.Load() {
+		t.Error("Shutdown() returned before the handler wrote its response")
+	}
+
+	select {
+	case resp := <-responses:
+		if resp.err != nil || resp.body != "aggregated" {
+			t.Errorf("response = %q, %v; want the aggregated result", resp.body, resp.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("client never received a response")
+	}
+}
@@ -0,0 +1,106 @@
+package coordinator
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"log"
+	"net/http"
+
+	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/signing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SignaturePolicy controls what happens to worker results whose signature cannot be verified
+type SignaturePolicy string
+
+const (
+	// SignaturesRequired drops unsigned or invalid results before aggregation
+	SignaturesRequired SignaturePolicy = "require"
+	// SignaturesFlagged keeps unverified results but marks them verified=false
+	SignaturesFlagged SignaturePolicy = "flag"
+	// SignaturesIgnored skips verification entirely
+	SignaturesIgnored SignaturePolicy = "off"
+)
+
+// ParseSignaturePolicy validates a signature policy name
+func ParseSignaturePolicy(name string) (SignaturePolicy, error) {
+	switch policy := SignaturePolicy(name); policy {
+	case SignaturesRequired, SignaturesFlagged, SignaturesIgnored:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown signature policy %q (use require, flag or off)", name)
+}
+
+// SetSignaturePolicy sets how unsigned or invalid worker results are handled
+func (c *Coordinator) SetSignaturePolicy(policy SignaturePolicy) {
+	c.signatures = policy
+}
+
+// verifyResult checks a worker result against the worker's registered public key.
+// It marks verified results and reports whether the result may be used.
+func (c *Coordinator) verifyResult(result *models.WorkerResult) bool {
+	result.Verified = false
+	if c.signatures == SignaturesIgnored {
+		return true
+	}
+
+	err := fmt.Errorf("worker %s has no registered public key", result.WorkerID)
+	if pub, exists := c.registry.PublicKey(result.WorkerID); exists {
+		err = signing.VerifyResult(pub, *result)
+	}
+	if err == nil {
+		result.Verified = true
+		return true
+	}
+
+	if c.signatures == SignaturesFlagged {
+		log.Printf("🚩 Flagging result from %s for request %s: %v", result.WorkerID, result.RequestID, err)
+		return true
+	}
+
+	log.Printf("🚫 Dropping result from %s for request %s: %v", result.WorkerID, result.RequestID, err)
+	return false
+}
+
+// SetSigningKey sets the key the coordinator signs aggregated reports with
+func (c *Coordinator) SetSigningKey(key ed25519.PrivateKey) {
+	c.key = key
+}
+
+// signReport attaches a signed report covering the result and its signed observations
+func (c *Coordinator) signReport(result *models.OracleResult) {
+	if c.key == nil {
+		return
+	}
+
+	observations := []models.WorkerResult{}
+	for _, response := range result.WorkerResponses {
+		if len(response.Signature) > 0 {
+			observations = append(observations, response)
+		}
+	}
+
+	result.Report = signing.SignReport(c.key, models.Report{
+		RequestID:    result.RequestID,
+		Query:        result.Query,
+		Timestamp:    result.Timestamp,
+		FinalValue:   result.FinalValue,
+		Strategy:     result.Strategy,
+		Observations: observations,
+	})
+}
+
+// handleKeys returns the coordinator's report signing key and every pinned worker key
+func (c *Coordinator) handleKeys(ctx *gin.Context) {
+	var coordinatorKey []byte
+	if c.key != nil {
+		coordinatorKey = c.key.Public().(ed25519.PublicKey)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"coordinator": coordinatorKey,
+		"workers":     c.registry.PublicKeys(),
+	})
+}
@@ -0,0 +1,59 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// DefaultTaskStream is the JetStream stream capturing published tasks
+	DefaultTaskStream = "ORACLE_TASKS"
+	// DefaultTaskMaxAge is how long an undelivered task stays in the task stream
+	DefaultTaskMaxAge = time.Minute
+)
+
+// UseTaskStream publishes tasks through a JetStream stream instead of core NATS, so
+// workers that are reconnecting receive them once they are back. The stream is
+// created if needed and keeps each task until every worker consumer acknowledged
+// it, or for at most maxAge.
+func (c *Coordinator) UseTaskStream(stream string, maxAge time.Duration) error {
+	js, err := c.nc.JetStream()
+	if err != nil {
+		return fmt.Errorf("failed to get JetStream context: %v", err)
+	}
+
+	cfg := &nats.StreamConfig{
+		Name:        stream,
+		Description: "Oracle tasks awaiting delivery to workers",
+		Subjects:    []string{c.subjects.Tasks},
+		Retention:   nats.InterestPolicy,
+		MaxAge:      maxAge,
+		Storage:     nats.FileStorage,
+	}
+	_, err = js.StreamInfo(stream)
+	switch {
+	case errors.Is(err, nats.ErrStreamNotFound):
+		_, err = js.AddStream(cfg)
+	case err == nil:
+		_, err = js.UpdateStream(cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set up task stream %s: %v", stream, err)
+	}
+
+	c.taskJS = js
+	log.Printf("📼 Publishing tasks through JetStream stream %s (max age %v)", stream, maxAge)
+	return nil
+}
+
+// taskDelivery names how tasks reach workers, for the health endpoint
+func (c *Coordinator) taskDelivery() string {
+	if c.taskJS != nil {
+		return "jetstream"
+	}
+	return "core"
+}
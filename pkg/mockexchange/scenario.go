@@ -0,0 +1,119 @@
+package mockexchange
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Phase types
+const (
+	PhaseSteady = "steady"
+	PhaseRamp   = "ramp"
+	PhaseCrash  = "crash"
+	PhaseStale  = "stale"
+	PhaseError  = "error"
+	PhaseSlow   = "slow"
+)
+
+// Scenario describes the tickers a mock exchange serves
+type Scenario struct {
+	Listen string `yaml:"listen" json:"listen"`
+	// Seed seeds the price noise, so a scenario replays the same quotes every run
+	Seed    int64    `yaml:"seed" json:"seed"`
+	Tickers []Ticker `yaml:"tickers" json:"tickers"`
+}
+
+// Ticker is a symbol whose price follows a scripted sequence of phases
+type Ticker struct {
+	Symbol string  `yaml:"symbol" json:"symbol"`
+	Price  float64 `yaml:"price" json:"price"`
+	// NoisePercent adds uniform random noise of ±NoisePercent to every quote
+	NoisePercent float64 `yaml:"noise_percent" json:"noise_percent"`
+	// Loop restarts the phases after the last one ends
+	Loop   bool    `yaml:"loop" json:"loop"`
+	Phases []Phase `yaml:"phases" json:"phases"`
+}
+
+// Phase is one step of a ticker's script
+type Phase struct {
+	// Type is steady, ramp, crash, stale, error or slow
+	Type     string        `yaml:"type" json:"type"`
+	Duration time.Duration `yaml:"duration" json:"duration"`
+	// Target is the price a ramp ends at
+	Target float64 `yaml:"target" json:"target"`
+	// DropPercent is how far a flash crash falls before recovering
+	DropPercent float64 `yaml:"drop_percent" json:"drop_percent"`
+	// Status is the HTTP status returned during an error phase (default 500)
+	Status int `yaml:"status" json:"status"`
+	// Delay is added to every response during a slow phase
+	Delay time.Duration `yaml:"delay" json:"delay"`
+}
+
+// LoadScenario reads a scenario from a YAML or JSON file.
+// Durations are written as strings such as "30s".
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %v", err)
+	}
+
+	// YAML is a superset of JSON, so one decoder handles both formats
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %v", err)
+	}
+
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// Validate checks that every ticker and phase is well formed
+func (s *Scenario) Validate() error {
+	if len(s.Tickers) == 0 {
+		return fmt.Errorf("scenario must define at least one ticker")
+	}
+
+	seen := make(map[string]bool)
+	for _, ticker := range s.Tickers {
+		if ticker.Symbol == "" {
+			return fmt.Errorf("ticker symbol is required")
+		}
+		if seen[ticker.Symbol] {
+			return fmt.Errorf("ticker %s is defined twice", ticker.Symbol)
+		}
+		seen[ticker.Symbol] = true
+
+		if ticker.Price <= 0 {
+			return fmt.Errorf("ticker %s: price must be positive", ticker.Symbol)
+		}
+
+		for i, phase := range ticker.Phases {
+			if phase.Duration <= 0 {
+				return fmt.Errorf("ticker %s phase %d: duration must be positive", ticker.Symbol, i)
+			}
+			switch phase.Type {
+			case PhaseSteady, PhaseStale, PhaseError:
+			case PhaseRamp:
+				if phase.Target <= 0 {
+					return fmt.Errorf("ticker %s phase %d: ramp target must be positive", ticker.Symbol, i)
+				}
+			case PhaseCrash:
+				if phase.DropPercent <= 0 || phase.DropPercent >= 100 {
+					return fmt.Errorf("ticker %s phase %d: drop_percent must be between 0 and 100", ticker.Symbol, i)
+				}
+			case PhaseSlow:
+				if phase.Delay <= 0 {
+					return fmt.Errorf("ticker %s phase %d: slow phase needs a delay", ticker.Symbol, i)
+				}
+			default:
+				return fmt.Errorf("ticker %s phase %d: unknown type %q", ticker.Symbol, i, phase.Type)
+			}
+		}
+	}
+	return nil
+}
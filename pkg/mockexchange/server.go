@@ -0,0 +1,182 @@
+package mockexchange
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Quote is the JSON body returned for a ticker
+type Quote struct {
+	Symbol    string  `json:"symbol"`
+	Price     float64 `json:"price"`
+	Phase     string  `json:"phase"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+// tickerState is what a ticker serves at a given moment
+type tickerState struct {
+	phase     Phase
+	price     float64
+	quotedAt  time.Time
+	noiseFree bool
+}
+
+// Server serves scripted tickers over HTTP
+type Server struct {
+	tickers map[string]Ticker
+	started time.Time
+	// rng generates the price noise from the scenario's seed
+	rng    *rand.Rand
+	rngMux sync.Mutex
+}
+
+// NewServer creates a mock exchange that starts its scripts now
+func NewServer(scenario *Scenario) *Server {
+	tickers := make(map[string]Ticker, len(scenario.Tickers))
+	for _, ticker := range scenario.Tickers {
+		tickers[ticker.Symbol] = ticker
+	}
+
+	return &Server{
+		tickers: tickers,
+		started: time.Now(),
+		rng:     rand.New(rand.NewSource(scenario.Seed)),
+	}
+}
+
+// noise returns a uniform random factor in [-1, 1)
+func (s *Server) noise() float64 {
+	s.rngMux.Lock()
+	defer s.rngMux.Unlock()
+
+	return s.rng.Float64()*2 - 1
+}
+
+// Handler returns the HTTP handler serving GET /ticker/{symbol} and GET /tickers
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ticker/", s.handleTicker)
+	mux.HandleFunc("/tickers", s.handleTickers)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// handleTicker serves the current quote for one symbol
+func (s *Server) handleTicker(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.TrimPrefix(r.URL.Path, "/ticker/")
+	ticker, exists := s.tickers[symbol]
+	if !exists {
+		http.Error(w, "unknown symbol", http.StatusNotFound)
+		return
+	}
+
+	state := s.stateAt(ticker, time.Now())
+	switch state.phase.Type {
+	case PhaseError:
+		status := state.phase.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		http.Error(w, "scripted upstream error", status)
+		return
+	case PhaseSlow:
+		select {
+		case <-time.After(state.phase.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	writeJSON(w, s.quote(ticker, state))
+}
+
+// handleTickers serves the current quote for every symbol
+func (s *Server) handleTickers(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	quotes := make([]Quote, 0, len(s.tickers))
+	for _, ticker := range s.tickers {
+		quotes = append(quotes, s.quote(ticker, s.stateAt(ticker, now)))
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].Symbol < quotes[j].Symbol })
+
+	writeJSON(w, quotes)
+}
+
+// quote builds the response body for a ticker state
+func (s *Server) quote(ticker Ticker, state tickerState) Quote {
+	price := state.price
+	if !state.noiseFree && ticker.NoisePercent > 0 {
+		price *= 1 + s.noise()*ticker.NoisePercent/100
+	}
+
+	return Quote{
+		Symbol:    ticker.Symbol,
+		Price:     price,
+		Phase:     state.phase.Type,
+		Timestamp: state.quotedAt.Unix(),
+	}
+}
+
+// stateAt walks the ticker's phases to find what it serves at now
+func (s *Server) stateAt(ticker Ticker, now time.Time) tickerState {
+	elapsed := now.Sub(s.started)
+	steady := tickerState{phase: Phase{Type: PhaseSteady}, price: ticker.Price, quotedAt: now}
+
+	var total time.Duration
+	for _, phase := range ticker.Phases {
+		total += phase.Duration
+	}
+	if total == 0 {
+		return steady
+	}
+	if ticker.Loop {
+		elapsed %= total
+	}
+
+	price := ticker.Price
+	phaseStart := s.started.Add(now.Sub(s.started) - elapsed)
+	for _, phase := range ticker.Phases {
+		if elapsed >= phase.Duration {
+			// Phase already finished; carry its end price forward
+			if phase.Type == PhaseRamp {
+				price = phase.Target
+			}
+			elapsed -= phase.Duration
+			phaseStart = phaseStart.Add(phase.Duration)
+			continue
+		}
+
+		progress := float64(elapsed) / float64(phase.Duration)
+		state := tickerState{phase: phase, price: price, quotedAt: now}
+		switch phase.Type {
+		case PhaseRamp:
+			state.price = price + (phase.Target-price)*progress
+		case PhaseCrash:
+			// Drop instantly, then recover linearly to the pre-crash price
+			bottom := price * (1 - phase.DropPercent/100)
+			state.price = bottom + (price-bottom)*progress
+		case PhaseStale:
+			// Frozen price and timestamp from the start of the phase
+			state.quotedAt = phaseStart
+			state.noiseFree = true
+		}
+		return state
+	}
+
+	// Script finished without looping: hold the final price
+	steady.price = price
+	return steady
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,104 @@
+package mockexchange
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// scriptedTicker runs through every price-moving phase once over 50 seconds
+func scriptedTicker(loop bool) Ticker {
+	return Ticker{
+		Symbol: "BTCUSD",
+		Price:  100,
+		Loop:   loop,
+		Phases: []Phase{
+			{Type: PhaseSteady, Duration: 10 * time.Second},
+			{Type: PhaseRamp, Duration: 10 * time.Second, Target: 200},
+			{Type: PhaseCrash, Duration: 10 * time.Second, DropPercent: 50},
+			{Type: PhaseStale, Duration: 10 * time.Second},
+			{Type: PhaseError, Duration: 10 * time.Second, Status: 503},
+		},
+	}
+}
+
+func TestStateAt(t *testing.T) {
+	started := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	server := &Server{started: started}
+
+	tests := []struct {
+		name         string
+		ticker       Ticker
+		elapsed      time.Duration
+		wantPhase    string
+		wantPrice    float64
+		wantQuotedAt time.Duration
+		wantFrozen   bool
+	}{
+		{"steady", scriptedTicker(false), 5 * time.Second, PhaseSteady, 100, 5 * time.Second, false},
+		{"ramp halfway", scriptedTicker(false), 15 * time.Second, PhaseRamp, 150, 15 * time.Second, false},
+		{"crash starts from the ramp target", scriptedTicker(false), 20 * time.Second, PhaseCrash, 100, 20 * time.Second, false},
+		{"crash recovers halfway", scriptedTicker(false), 25 * time.Second, PhaseCrash, 150, 25 * time.Second, false},
+		{"crash recovered into stale", scriptedTicker(false), 30 * time.Second, PhaseStale, 200, 30 * time.Second, true},
+		{"stale keeps the phase start timestamp", scriptedTicker(false), 38 * time.Second, PhaseStale, 200, 30 * time.Second, true},
+		{"error", scriptedTicker(false), 45 * time.Second, PhaseError, 200, 45 * time.Second, false},
+		{"finished script holds the last price", scriptedTicker(false), 2 * time.Minute, PhaseSteady, 200, 2 * time.Minute, false},
+		{"loop wraps to the start price", scriptedTicker(true), 55 * time.Second, PhaseSteady, 100, 55 * time.Second, false},
+		{"loop wraps into a ramp", scriptedTicker(true), 115 * time.Second, PhaseRamp, 150, 115 * time.Second, false},
+		{"stale timestamp in a later loop", scriptedTicker(true), 88 * time.Second, PhaseStale, 200, 80 * time.Second, true},
+		{"no phases", Ticker{Symbol: "ETHUSD", Price: 2500}, time.Hour, PhaseSteady, 2500, time.Hour, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := server.stateAt(tt.ticker, started.Add(tt.elapsed))
+			if state.phase.Type != tt.wantPhase {
+				t.Errorf("phase = %s, want %s", state.phase.Type, tt.wantPhase)
+			}
+			if math.Abs(state.price-tt.wantPrice) > 1e-9 {
+				t.Errorf("price = %v, want %v", state.price, tt.wantPrice)
+			}
+			if want := started.Add(tt.wantQuotedAt); !state.quotedAt.Equal(want) {
+				t.Errorf("quotedAt = %v, want %v", state.quotedAt, want)
+			}
+			if state.noiseFree != tt.wantFrozen {
+				t.Errorf("noiseFree = %v, want %v", state.noiseFree, tt.wantFrozen)
+			}
+		})
+	}
+}
+
+func TestNoiseIsSeeded(t *testing.T) {
+	ticker := Ticker{Symbol: "BTCUSD", Price: 100, NoisePercent: 1}
+	quotes := func(seed int64) []float64 {
+		server := NewServer(&Scenario{Seed: seed, Tickers: []Ticker{ticker}})
+		state := server.stateAt(ticker, server.started)
+		prices := make([]float64, 20)
+		for i := range prices {
+			prices[i] = server.quote(ticker, state).Price
+		}
+		return prices
+	}
+
+	first, replay, other := quotes(42), quotes(42), quotes(7)
+	differs := false
+	for i := range first {
+		if first[i] != replay[i] {
+			t.Fatalf("quote %d = %v on replay, want %v", i, replay[i], first[i])
+		}
+		if first[i] < 99 || first[i] > 101 {
+			t.Errorf("quote %d = %v, outside the 1%% noise band", i, first[i])
+		}
+		differs = differs || first[i] != other[i]
+	}
+	if !differs {
+		t.Error("different seeds produced the same quotes")
+	}
+
+	// Stale phases serve a frozen price without noise
+	server := NewServer(&Scenario{Seed: 42, Tickers: []Ticker{ticker}})
+	stale := tickerState{phase: Phase{Type: PhaseStale}, price: 100, noiseFree: true}
+	if got := server.quote(ticker, stale).Price; got != 100 {
+		t.Errorf("stale quote = %v, want 100", got)
+	}
+}
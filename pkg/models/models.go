@@ -2,44 +2,327 @@ package models
 
 import "time"
 
+// NATS subjects shared by the coordinator and workers
+const (
+	SubjectTasks     = "oracle.tasks"
+	SubjectResults   = "oracle.results"
+	SubjectRegister  = "oracle.register"
+	SubjectHeartbeat = "oracle.heartbeat"
+	// SubjectDeregister is published by workers that are shutting down
+	SubjectDeregister = "oracle.deregister"
+	// SubjectFeedPrefix is followed by the feed query, e.g. oracle.feeds.BTC/USD
+	SubjectFeedPrefix = "oracle.feeds."
+)
+
+// Subjects holds the NATS subjects used between the coordinator and workers
+type Subjects struct {
+	Tasks      string `yaml:"tasks" json:"tasks"`
+	Results    string `yaml:"results" json:"results"`
+	Register   string `yaml:"register" json:"register"`
+	Heartbeat  string `yaml:"heartbeat" json:"heartbeat"`
+	Deregister string `yaml:"deregister" json:"deregister"`
+	FeedPrefix string `yaml:"feed_prefix" json:"feed_prefix"`
+}
+
+// DefaultSubjects returns the standard oracle subjects
+func DefaultSubjects() Subjects {
+	return Subjects{
+		Tasks:      SubjectTasks,
+		Results:    SubjectResults,
+		Register:   SubjectRegister,
+		Heartbeat:  SubjectHeartbeat,
+		Deregister: SubjectDeregister,
+		FeedPrefix: SubjectFeedPrefix,
+	}
+}
+
 // OracleRequest represents a request to fetch data from oracles
 type OracleRequest struct {
-	ID    string `json:"id"`
-	Query string `json:"query"`
+	ID      string         `json:"id"`
+	Query   string         `json:"query"`
+	Options RequestOptions `json:"options"`
+	// ReplyTo is the subject workers publish their results to. It is set by the
+	// coordinator that owns the request; empty means the shared results subject.
+	ReplyTo string `json:"reply_to,omitempty"`
+}
+
+// RequestOptions controls how the coordinator collects responses for a request.
+// Zero values fall back to the coordinator's defaults.
+type RequestOptions struct {
+	// TimeoutMs is how long the coordinator collects worker responses, in milliseconds
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+	// Strategy names the aggregation strategy used to compute the final value
+	Strategy string `json:"strategy,omitempty"`
+	// QuorumResponses completes the request as soon as this many workers responded
+	QuorumResponses int `json:"quorum_responses,omitempty"`
+	// QuorumFraction completes the request once this fraction of registered workers responded
+	QuorumFraction float64 `json:"quorum_fraction,omitempty"`
+	// MinResponses is the number of successful responses required for the request to succeed
+	MinResponses int `json:"min_responses,omitempty"`
+	// MaxResponses stops collection once this many workers responded
+	MaxResponses int `json:"max_responses,omitempty"`
+}
+
+// Timeout returns the collection timeout as a duration
+func (o RequestOptions) Timeout() time.Duration {
+	return time.Duration(o.TimeoutMs) * time.Millisecond
 }
 
 // WorkerResult represents the response from a worker
 type WorkerResult struct {
-	WorkerID     string        `json:"worker_id"`
-	RequestID    string        `json:"request_id"`
+	WorkerID string `json:"worker_id"`
+	// InstanceID identifies the worker process that produced the result
+	InstanceID string `json:"instance_id,omitempty"`
+	RequestID  string `json:"request_id"`
+	// Query is the query the worker answered, copied from the task
+	Query string `json:"query"`
+	// Timestamp is when the worker produced the result, in UTC
+	Timestamp    time.Time     `json:"timestamp"`
 	Value        float64       `json:"value"`
 	Err          string        `json:"err,omitempty"`
+	Source       string        `json:"source,omitempty"`
 	ResponseTime time.Duration `json:"response_time"`
+	// Saturated is set when the worker had no free capacity and skipped the task
+	Saturated bool `json:"saturated,omitempty"`
+	// Signature is the worker's ed25519 signature over the result
+	Signature []byte `json:"signature,omitempty"`
+	// Verified is set by the coordinator once the signature checked out
+	Verified bool `json:"verified"`
 }
 
 // OracleResult represents the final aggregated response
 type OracleResult struct {
 	RequestID       string         `json:"request_id"`
+	Query           string         `json:"query"`
+	Timestamp       time.Time      `json:"timestamp"`
 	FinalValue      float64        `json:"final_value"`
+	Strategy        string         `json:"strategy"`
 	WorkerResponses []WorkerResult `json:"worker_responses"`
-	ReliabilityNote string         `json:"reliability_note"`
+	// TimedOutWorkers lists the registered workers that did not respond in time
+	TimedOutWorkers []string      `json:"timed_out_workers,omitempty"`
+	ReliabilityNote string        `json:"reliability_note"`
+	Report          *SignedReport `json:"report,omitempty"`
+}
+
+// Report is the canonical content of an aggregated result that the coordinator signs
+type Report struct {
+	RequestID  string    `json:"request_id"`
+	Query      string    `json:"query"`
+	Timestamp  time.Time `json:"timestamp"`
+	FinalValue float64   `json:"final_value"`
+	Strategy   string    `json:"strategy"`
+	// Observations are the signed worker results the final value was computed from, sorted by worker ID
+	Observations []WorkerResult `json:"observations"`
+}
+
+// SignedReport carries the canonical report encoding and the coordinator's signature over it
+type SignedReport struct {
+	// Payload is the canonical JSON encoding of a Report
+	Payload []byte `json:"payload"`
+	// Signature is the coordinator's ed25519 signature over Payload
+	Signature []byte `json:"signature"`
+	// CoordinatorKey is the coordinator's ed25519 public key
+	CoordinatorKey []byte `json:"coordinator_key"`
 }
 
+// Worker status values reported by the coordinator's registry
+const (
+	WorkerStatusActive  = "active"
+	WorkerStatusSuspect = "suspect"
+)
+
 // WorkerInfo represents information about a registered worker
 type WorkerInfo struct {
-	ID       string    `json:"id"`
-	Endpoint string    `json:"endpoint"`
-	LastSeen time.Time `json:"last_seen"`
+	ID           string    `json:"id"`
+	Endpoint     string    `json:"endpoint"`
+	Status       string    `json:"status"`
+	InstanceID   string    `json:"instance_id,omitempty"`
+	InFlight     int       `json:"in_flight"`
+	MaxInFlight  int       `json:"max_in_flight"`
+	PublicKey    []byte    `json:"public_key,omitempty"`
+	RegisteredAt time.Time `json:"registered_at"`
+	LastSeen     time.Time `json:"last_seen"`
 }
 
 // RegisterRequest represents a worker registration request
 type RegisterRequest struct {
-	ID       string `json:"id"`
-	Endpoint string `json:"endpoint"`
+	ID          string    `json:"id"`
+	InstanceID  string    `json:"instance_id,omitempty"`
+	Endpoint    string    `json:"endpoint"`
+	PublicKey   []byte    `json:"public_key,omitempty"`
+	MaxInFlight int       `json:"max_in_flight"`
+	Timestamp   time.Time `json:"timestamp"`
+	// Signature is the worker's ed25519 signature over the registration
+	Signature []byte `json:"signature,omitempty"`
 }
 
 // RegisterResponse represents the response to a worker registration
 type RegisterResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
+	// Reason classifies a rejection, e.g. RegisterReasonDuplicate
+	Reason string `json:"reason,omitempty"`
+}
+
+// RegisterReasonDuplicate rejects a registration because another live process holds the worker ID
+const RegisterReasonDuplicate = "duplicate"
+
+// Heartbeat is published periodically by workers to signal they are alive
+type Heartbeat struct {
+	ID          string    `json:"id"`
+	InstanceID  string    `json:"instance_id,omitempty"`
+	Endpoint    string    `json:"endpoint"`
+	PublicKey   []byte    `json:"public_key,omitempty"`
+	InFlight    int       `json:"in_flight"`
+	MaxInFlight int       `json:"max_in_flight"`
+	Timestamp   time.Time `json:"timestamp"`
+	// Signature is the worker's ed25519 signature over the heartbeat
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Deregistration is published by a worker when it shuts down
+type Deregistration struct {
+	ID         string    `json:"id"`
+	InstanceID string    `json:"instance_id,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	// Signature is the worker's ed25519 signature over the deregistration
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// ResultMetrics counts worker results received by a coordinator
+type ResultMetrics struct {
+	// Received results were delivered to a request that was collecting
+	Received uint64 `json:"received"`
+	// Dropped results could not be delivered to a collecting request
+	Dropped uint64 `json:"dropped"`
+	// Late results arrived after their request stopped collecting
+	Late uint64 `json:"late"`
+	// Unknown results named a request this coordinator never handled
+	Unknown uint64 `json:"unknown"`
+}
+
+// WorkerStats tracks a worker's reliability over time
+type WorkerStats struct {
+	WorkerID       string        `json:"worker_id"`
+	SuccessCount   int           `json:"success_count"`
+	FailureCount   int           `json:"failure_count"`
+	SaturatedCount int           `json:"saturated_count"`
+	TimeoutCount   int           `json:"timeout_count"`
+	LateCount      int           `json:"late_count"`
+	AvgLatency     time.Duration `json:"avg_latency"`
+	AvgDeviation   float64       `json:"avg_deviation"`
+	Score          float64       `json:"score"`
+	Reliable       bool          `json:"reliable"`
+	LastUpdated    time.Time     `json:"last_updated"`
+}
+
+// Request lifecycle states reported by the coordinator
+const (
+	RequestStatusQueued      = "queued"
+	RequestStatusDispatched  = "dispatched"
+	RequestStatusAggregating = "aggregating"
+	RequestStatusComplete    = "complete"
+	RequestStatusFailed      = "failed"
+)
+
+// RequestState tracks the progress of an oracle request
+type RequestState struct {
+	RequestID   string        `json:"request_id"`
+	Query       string        `json:"query"`
+	Status      string        `json:"status"`
+	Error       string        `json:"error,omitempty"`
+	Result      *OracleResult `json:"result,omitempty"`
+	SubmittedAt time.Time     `json:"submitted_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// SharedRequest is the replicated state of a request kept in the JetStream KV bucket,
+// so another coordinator replica can finish it if its owner dies
+type SharedRequest struct {
+	Request OracleRequest `json:"request"`
+	// Owner is the ID of the coordinator collecting results for the request
+	Owner string `json:"owner"`
+	// LeaseExpires is when other replicas may take the request over
+	LeaseExpires time.Time `json:"lease_expires"`
+	Status       string    `json:"status"`
+	// Deadline is when result collection ends
+	Deadline    time.Time      `json:"deadline"`
+	Results     []WorkerResult `json:"results"`
+	Result      *OracleResult  `json:"result,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	SubmittedAt time.Time      `json:"submitted_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// Request event types emitted on a request's event stream
+const (
+	EventStatus       = "status"
+	EventWorkerResult = "worker_result"
+	EventProvisional  = "provisional"
+	EventFinal        = "final"
+)
+
+// RequestEvent is a single update on a request's event stream
+type RequestEvent struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// ProvisionalResult is the running aggregate while worker results are still arriving
+type ProvisionalResult struct {
+	RequestID string  `json:"request_id"`
+	Value     float64 `json:"value"`
+	Strategy  string  `json:"strategy"`
+	Responses int     `json:"responses"`
+}
+
+// FeedDefinition describes a recurring price feed run by the coordinator
+type FeedDefinition struct {
+	Query string `json:"query"`
+	// IntervalMs is how often the feed queries the workers, in milliseconds
+	IntervalMs int `json:"interval_ms"`
+	// DeviationPercent publishes a new value when it moves more than this from the last published value
+	DeviationPercent float64 `json:"deviation_percent"`
+	// HeartbeatMs publishes a value at least this often even if it has not moved, in milliseconds
+	HeartbeatMs int `json:"heartbeat_ms"`
+	// Options are applied to every request the feed makes
+	Options RequestOptions `json:"options"`
+}
+
+// Interval returns the feed's polling interval as a duration
+func (f FeedDefinition) Interval() time.Duration {
+	return time.Duration(f.IntervalMs) * time.Millisecond
+}
+
+// Heartbeat returns the feed's maximum time between publications as a duration
+func (f FeedDefinition) Heartbeat() time.Duration {
+	return time.Duration(f.HeartbeatMs) * time.Millisecond
+}
+
+// FeedState reports a feed's definition together with its latest activity
+type FeedState struct {
+	FeedDefinition
+	LastValue       float64       `json:"last_value"`
+	LastPublishedAt time.Time     `json:"last_published_at"`
+	LastResult      *OracleResult `json:"last_result,omitempty"`
+	LastRoundAt     time.Time     `json:"last_round_at"`
+	LastError       string        `json:"last_error,omitempty"`
+	Rounds          int           `json:"rounds"`
+	Publications    int           `json:"publications"`
+}
+
+// Feed publication reasons
+const (
+	FeedReasonInitial   = "initial"
+	FeedReasonDeviation = "deviation"
+	FeedReasonHeartbeat = "heartbeat"
+)
+
+// FeedUpdate is published on the feed's NATS subject whenever a new value is published
+type FeedUpdate struct {
+	Query       string       `json:"query"`
+	Value       float64      `json:"value"`
+	Reason      string       `json:"reason"`
+	Result      OracleResult `json:"result"`
+	PublishedAt time.Time    `json:"published_at"`
 }
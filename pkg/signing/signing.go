@@ -0,0 +1,257 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+)
+
+var (
+	// ErrUnsigned is returned when a result, report or worker control message carries no signature
+	ErrUnsigned = errors.New("result is not signed")
+	// ErrInvalidSignature is returned when a signature does not match the result
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// LoadOrCreateKey reads an ed25519 private key from path, generating and saving
+// a new one if the file does not exist. The file holds the hex-encoded seed.
+func LoadOrCreateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("key file %s is not a valid ed25519 seed", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	}
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create key directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(priv.Seed())+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write key file: %v", err)
+	}
+	return priv, nil
+}
+
+// WorkerID derives a stable worker ID from a worker's public key, so a worker
+// keeps its identity across restarts as long as its key file is kept
+func WorkerID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "worker-" + hex.EncodeToString(sum[:6])
+}
+
+// resultPayload is the canonical form of a worker result that gets signed.
+// Field order is fixed by the struct, so the JSON encoding is deterministic.
+type resultPayload struct {
+	WorkerID     string        `json:"worker_id"`
+	InstanceID   string        `json:"instance_id"`
+	RequestID    string        `json:"request_id"`
+	Query        string        `json:"query"`
+	Timestamp    time.Time     `json:"timestamp"`
+	Value        float64       `json:"value"`
+	Err          string        `json:"err"`
+	Source       string        `json:"source"`
+	ResponseTime time.Duration `json:"response_time"`
+	Saturated    bool          `json:"saturated,omitempty"`
+}
+
+// ResultPayload returns the canonical bytes signed for a worker result. The query
+// and timestamp are covered, so an observation cannot be replayed for another
+// query under a reused request ID.
+func ResultPayload(result models.WorkerResult) []byte {
+	payload, _ := json.Marshal(resultPayload{
+		WorkerID:     result.WorkerID,
+		InstanceID:   result.InstanceID,
+		RequestID:    result.RequestID,
+		Query:        result.Query,
+		Timestamp:    result.Timestamp.UTC(),
+		Value:        result.Value,
+		Err:          result.Err,
+		Source:       result.Source,
+		ResponseTime: result.ResponseTime,
+		Saturated:    result.Saturated,
+	})
+	return payload
+}
+
+// SignResult signs a worker result in place
+func SignResult(priv ed25519.PrivateKey, result *models.WorkerResult) {
+	result.Signature = ed25519.Sign(priv, ResultPayload(*result))
+}
+
+// VerifyResult checks a worker result's signature against pub
+func VerifyResult(pub ed25519.PublicKey, result models.WorkerResult) error {
+	return verify(pub, ResultPayload(result), result.Signature)
+}
+
+// Worker control message kinds. The kind is part of the signed payload, so a
+// signature over one kind of message cannot be replayed as another.
+const (
+	controlRegister   = "register"
+	controlHeartbeat  = "heartbeat"
+	controlDeregister = "deregister"
+)
+
+// controlPayload is the canonical form of a worker control message that gets signed
+type controlPayload struct {
+	Kind        string    `json:"kind"`
+	WorkerID    string    `json:"worker_id"`
+	InstanceID  string    `json:"instance_id"`
+	Endpoint    string    `json:"endpoint"`
+	PublicKey   []byte    `json:"public_key"`
+	InFlight    int       `json:"in_flight"`
+	MaxInFlight int       `json:"max_in_flight"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// encode returns the canonical bytes of a control payload
+func (p controlPayload) encode() []byte {
+	p.Timestamp = p.Timestamp.UTC()
+	payload, _ := json.Marshal(p)
+	return payload
+}
+
+// verify checks a signature over payload against pub
+func verify(pub ed25519.PublicKey, payload, signature []byte) error {
+	if len(signature) == 0 {
+		return ErrUnsigned
+	}
+	if len(pub) != ed25519.PublicKeySize || !ed25519.Verify(pub, payload, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// RegisterPayload returns the canonical bytes signed for a worker registration
+func RegisterPayload(req models.RegisterRequest) []byte {
+	return controlPayload{
+		Kind:        controlRegister,
+		WorkerID:    req.ID,
+		InstanceID:  req.InstanceID,
+		Endpoint:    req.Endpoint,
+		PublicKey:   req.PublicKey,
+		MaxInFlight: req.MaxInFlight,
+		Timestamp:   req.Timestamp,
+	}.encode()
+}
+
+// SignRegister signs a worker registration in place
+func SignRegister(priv ed25519.PrivateKey, req *models.RegisterRequest) {
+	req.Signature = ed25519.Sign(priv, RegisterPayload(*req))
+}
+
+// VerifyRegister checks a worker registration's signature against pub
+func VerifyRegister(pub ed25519.PublicKey, req models.RegisterRequest) error {
+	return verify(pub, RegisterPayload(req), req.Signature)
+}
+
+// HeartbeatPayload returns the canonical bytes signed for a worker heartbeat
+func HeartbeatPayload(hb models.Heartbeat) []byte {
+	return controlPayload{
+		Kind:        controlHeartbeat,
+		WorkerID:    hb.ID,
+		InstanceID:  hb.InstanceID,
+		Endpoint:    hb.Endpoint,
+		PublicKey:   hb.PublicKey,
+		InFlight:    hb.InFlight,
+		MaxInFlight: hb.MaxInFlight,
+		Timestamp:   hb.Timestamp,
+	}.encode()
+}
+
+// SignHeartbeat signs a worker heartbeat in place
+func SignHeartbeat(priv ed25519.PrivateKey, hb *models.Heartbeat) {
+	hb.Signature = ed25519.Sign(priv, HeartbeatPayload(*hb))
+}
+
+// VerifyHeartbeat checks a worker heartbeat's signature against pub
+func VerifyHeartbeat(pub ed25519.PublicKey, hb models.Heartbeat) error {
+	return verify(pub, HeartbeatPayload(hb), hb.Signature)
+}
+
+// DeregisterPayload returns the canonical bytes signed for a worker deregistration
+func DeregisterPayload(dereg models.Deregistration) []byte {
+	return controlPayload{
+		Kind:       controlDeregister,
+		WorkerID:   dereg.ID,
+		InstanceID: dereg.InstanceID,
+		Timestamp:  dereg.Timestamp,
+	}.encode()
+}
+
+// SignDeregister signs a worker deregistration in place
+func SignDeregister(priv ed25519.PrivateKey, dereg *models.Deregistration) {
+	dereg.Signature = ed25519.Sign(priv, DeregisterPayload(*dereg))
+}
+
+// VerifyDeregister checks a worker deregistration's signature against pub
+func VerifyDeregister(pub ed25519.PublicKey, dereg models.Deregistration) error {
+	return verify(pub, DeregisterPayload(dereg), dereg.Signature)
+}
+
+// ReportPayload returns the canonical encoding of a report.
+// Observations are sorted by worker ID and stripped of coordinator-only fields.
+func ReportPayload(report models.Report) []byte {
+	observations := make([]models.WorkerResult, len(report.Observations))
+	copy(observations, report.Observations)
+	for i := range observations {
+		observations[i].Verified = false
+	}
+	sort.Slice(observations, func(i, j int) bool {
+		return observations[i].WorkerID < observations[j].WorkerID
+	})
+	report.Observations = observations
+	report.Timestamp = report.Timestamp.UTC()
+
+	payload, _ := json.Marshal(report)
+	return payload
+}
+
+// SignReport encodes a report canonically and signs it with the coordinator's key
+func SignReport(priv ed25519.PrivateKey, report models.Report) *models.SignedReport {
+	payload := ReportPayload(report)
+	return &models.SignedReport{
+		Payload:        payload,
+		Signature:      ed25519.Sign(priv, payload),
+		CoordinatorKey: priv.Public().(ed25519.PublicKey),
+	}
+}
+
+// OpenReport verifies the coordinator's signature on a signed report against pub
+// and decodes the report
+func OpenReport(pub ed25519.PublicKey, signed models.SignedReport) (models.Report, error) {
+	var report models.Report
+	if len(signed.Signature) == 0 {
+		return report, ErrUnsigned
+	}
+	if len(pub) != ed25519.PublicKeySize || !ed25519.Verify(pub, signed.Payload, signed.Signature) {
+		return report, ErrInvalidSignature
+	}
+
+	if err := json.Unmarshal(signed.Payload, &report); err != nil {
+		return report, fmt.Errorf("failed to decode report: %v", err)
+	}
+	return report, nil
+}
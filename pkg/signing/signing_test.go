@@ -0,0 +1,200 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+)
+
+// testKey returns a deterministic key pair for the given seed byte
+func testKey(b byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, ed25519.SeedSize))
+}
+
+func signedResult(priv ed25519.PrivateKey) models.WorkerResult {
+	result := models.WorkerResult{
+		WorkerID:     "worker-1",
+		InstanceID:   "instance-1",
+		RequestID:    "req-1",
+		Query:        "BTC/USD",
+		Timestamp:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Value:        42000.5,
+		Source:       "exchange-a",
+		ResponseTime: 120 * time.Millisecond,
+	}
+	SignResult(priv, &result)
+	return result
+}
+
+func TestVerifyResult(t *testing.T) {
+	priv := testKey(1)
+	pub := priv.Public().(ed25519.PublicKey)
+
+	tests := []struct {
+		name    string
+		pub     ed25519.PublicKey
+		tamper  func(result *models.WorkerResult)
+		wantErr error
+	}{
+		{"valid", pub, func(*models.WorkerResult) {}, nil},
+		{"timestamp in another zone", pub, func(r *models.WorkerResult) {
+			r.Timestamp = r.Timestamp.In(time.FixedZone("UTC+2", 2*60*60))
+		}, nil},
+		{"coordinator-only field", pub, func(r *models.WorkerResult) { r.Verified = true }, nil},
+		{"unsigned", pub, func(r *models.WorkerResult) { r.Signature = nil }, ErrUnsigned},
+		{"wrong key", testKey(2).Public().(ed25519.PublicKey), func(*models.WorkerResult) {}, ErrInvalidSignature},
+		{"short key", pub[:10], func(*models.WorkerResult) {}, ErrInvalidSignature},
+		{"worker id", pub, func(r *models.WorkerResult) { r.WorkerID = "worker-2" }, ErrInvalidSignature},
+		{"instance id", pub, func(r *models.WorkerResult) { r.InstanceID = "instance-2" }, ErrInvalidSignature},
+		{"request id", pub, func(r *models.WorkerResult) { r.RequestID = "req-2" }, ErrInvalidSignature},
+		{"query", pub, func(r *models.WorkerResult) { r.Query = "ETH/USD" }, ErrInvalidSignature},
+		{"timestamp", pub, func(r *models.WorkerResult) { r.Timestamp = r.Timestamp.Add(time.Second) }, ErrInvalidSignature},
+		{"value", pub, func(r *models.WorkerResult) { r.Value++ }, ErrInvalidSignature},
+		{"error", pub, func(r *models.WorkerResult) { r.Err = "failed" }, ErrInvalidSignature},
+		{"source", pub, func(r *models.WorkerResult) { r.Source = "exchange-b" }, ErrInvalidSignature},
+		{"response time", pub, func(r *models.WorkerResult) { r.ResponseTime++ }, ErrInvalidSignature},
+		{"saturated", pub, func(r *models.WorkerResult) { r.Saturated = true }, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := signedResult(priv)
+			tt.tamper(&result)
+			if err := VerifyResult(tt.pub, result); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyResult() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenReport(t *testing.T) {
+	priv := testKey(1)
+	pub := priv.Public().(ed25519.PublicKey)
+	report := models.Report{
+		RequestID:    "req-1",
+		Query:        "BTC/USD",
+		Timestamp:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		FinalValue:   42000.5,
+		Strategy:     "median",
+		Observations: []models.WorkerResult{signedResult(priv)},
+	}
+	signed := SignReport(priv, report)
+
+	tampered := *signed
+	tampered.Payload = bytes.Replace(signed.Payload, []byte("42000.5"), []byte("42001.5"), 1)
+	unsigned := *signed
+	unsigned.Signature = nil
+
+	tests := []struct {
+		name    string
+		pub     ed25519.PublicKey
+		signed  models.SignedReport
+		wantErr error
+	}{
+		{"valid", pub, *signed, nil},
+		{"tampered payload", pub, tampered, ErrInvalidSignature},
+		{"unsigned", pub, unsigned, ErrUnsigned},
+		{"wrong key", testKey(2).Public().(ed25519.PublicKey), *signed, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OpenReport(tt.pub, tt.signed)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OpenReport() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.RequestID != report.RequestID || got.FinalValue != report.FinalValue ||
+				len(got.Observations) != 1) {
+				t.Errorf("OpenReport() = %+v, want %+v", got, report)
+			}
+		})
+	}
+}
+
+func TestReportPayloadIsCanonical(t *testing.T) {
+	a := models.WorkerResult{WorkerID: "worker-a", Value: 1}
+	b := models.WorkerResult{WorkerID: "worker-b", Value: 2, Verified: true}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first := ReportPayload(models.Report{RequestID: "req-1", Timestamp: at, Observations: []models.WorkerResult{a, b}})
+	second := ReportPayload(models.Report{RequestID: "req-1", Timestamp: at.Local(), Observations: []models.WorkerResult{b, a}})
+	if !bytes.Equal(first, second) {
+		t.Errorf("ReportPayload() differs by observation order or time zone:\n%s\n%s", first, second)
+	}
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "worker.key")
+
+	created, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey() creating: %v", err)
+	}
+	loaded, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey() loading: %v", err)
+	}
+	if !created.Equal(loaded) {
+		t.Error("LoadOrCreateKey() returned a different key for an existing file")
+	}
+	if WorkerID(created.Public().(ed25519.PublicKey)) != WorkerID(loaded.Public().(ed25519.PublicKey)) {
+		t.Error("WorkerID() is not stable for the same key")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.key")
+	if err := os.WriteFile(invalid, []byte("not hex"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOrCreateKey(invalid); err == nil {
+		t.Error("LoadOrCreateKey() accepted an invalid key file")
+	}
+}
+
+func TestVerifyControlMessages(t *testing.T) {
+	priv := testKey(1)
+	pub := priv.Public().(ed25519.PublicKey)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	req := models.RegisterRequest{ID: "worker-1", InstanceID: "a", PublicKey: pub, Timestamp: at}
+	SignRegister(priv, &req)
+	hb := models.Heartbeat{ID: "worker-1", InstanceID: "a", PublicKey: pub, Timestamp: at}
+	SignHeartbeat(priv, &hb)
+	dereg := models.Deregistration{ID: "worker-1", InstanceID: "a", Timestamp: at}
+	SignDeregister(priv, &dereg)
+
+	// A heartbeat signature must not pass as a registration with the same fields
+	asRegister := req
+	asRegister.Signature = hb.Signature
+	otherInstance := dereg
+	otherInstance.InstanceID = "b"
+
+	tests := []struct {
+		name    string
+		verify  func() error
+		wantErr error
+	}{
+		{"register", func() error { return VerifyRegister(pub, req) }, nil},
+		{"heartbeat", func() error { return VerifyHeartbeat(pub, hb) }, nil},
+		{"deregister", func() error { return VerifyDeregister(pub, dereg) }, nil},
+		{"heartbeat signature as registration", func() error { return VerifyRegister(pub, asRegister) }, ErrInvalidSignature},
+		{"deregister another instance", func() error { return VerifyDeregister(pub, otherInstance) }, ErrInvalidSignature},
+		{"unsigned deregister", func() error {
+			return VerifyDeregister(pub, models.Deregistration{ID: "worker-1", InstanceID: "a", Timestamp: at})
+		}, ErrUnsigned},
+		{"wrong key", func() error { return VerifyHeartbeat(testKey(2).Public().(ed25519.PublicKey), hb) }, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.verify(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
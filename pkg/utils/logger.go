@@ -22,8 +22,8 @@ func LogWorkerResult(res models.WorkerResult) {
 
 // LogOracleResult logs final aggregated result
 func LogOracleResult(result models.OracleResult) {
-	log.Printf("🎯 Final Result: value=%.2f, workers=%d, note='%s'",
-		result.FinalValue, len(result.WorkerResponses), result.ReliabilityNote)
+	log.Printf("🎯 Final Result: value=%.2f, strategy=%s, workers=%d, note='%s'",
+		result.FinalValue, result.Strategy, len(result.WorkerResponses), result.ReliabilityNote)
 
 	for _, res := range result.WorkerResponses {
 		LogWorkerResult(res)
@@ -40,6 +40,16 @@ func GenerateWorkerID() string {
 	return fmt.Sprintf("worker-%s", uuid.New().String()[:8])
 }
 
+// GenerateCoordinatorID creates unique IDs for coordinators
+func GenerateCoordinatorID() string {
+	return fmt.Sprintf("coord-%s", uuid.New().String()[:8])
+}
+
+// GenerateInstanceID creates a unique ID for a single worker process
+func GenerateInstanceID() string {
+	return uuid.New().String()
+}
+
 // CalculateReliability calculates worker reliability based on success rate
 func CalculateReliability(successCount, totalCount int) bool {
 	if totalCount == 0 {
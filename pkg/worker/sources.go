@@ -0,0 +1,273 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Data source types
+const (
+	SourceTypeSimulated = "simulated"
+	SourceTypeHTTP      = "http"
+)
+
+// defaultSourceTimeout bounds a single fetch when a source does not set its own timeout
+const defaultSourceTimeout = 3 * time.Second
+
+// DataSource fetches the current value for an oracle query
+type DataSource interface {
+	// Name identifies the source in worker results and logs
+	Name() string
+	// Fetch returns the value for query, honouring ctx cancellation
+	Fetch(ctx context.Context, query string) (float64, error)
+}
+
+// SourceConfig configures a data source adapter
+type SourceConfig struct {
+	Name string `json:"name"`
+	// Type is "http" or "simulated"
+	Type string `json:"type"`
+	// URL is the endpoint to fetch. {symbol}, {base} and {quote} are replaced per query.
+	URL string `json:"url,omitempty"`
+	// Path is a dot-separated path to the price in the JSON response, e.g. "data.0.price"
+	Path string `json:"path,omitempty"`
+	// Scale multiplies the extracted value, e.g. 1e-8 for prices quoted in satoshis
+	Scale float64 `json:"scale,omitempty"`
+	// TimeoutMs bounds each fetch, in milliseconds
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+	// Queries lists the queries this source serves; empty or "*" serves every query
+	Queries []string `json:"queries,omitempty"`
+	// Symbols maps queries to the source's own symbol, e.g. "BTC/USD" -> "BTCUSDT"
+	Symbols map[string]string `json:"symbols,omitempty"`
+	// Headers are added to every HTTP request
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Timeout returns the per-fetch timeout as a duration
+func (c SourceConfig) Timeout() time.Duration {
+	if c.TimeoutMs <= 0 {
+		return defaultSourceTimeout
+	}
+	return time.Duration(c.TimeoutMs) * time.Millisecond
+}
+
+// serves reports whether the source is configured to answer query
+func (c SourceConfig) serves(query string) bool {
+	if len(c.Queries) == 0 {
+		return true
+	}
+	for _, q := range c.Queries {
+		if q == "*" || q == query {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadSourceConfigs reads a JSON array of source configurations from path
+func LoadSourceConfigs(path string) ([]SourceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sources file: %v", err)
+	}
+
+	var configs []SourceConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse sources file: %v", err)
+	}
+	return configs, nil
+}
+
+// NewSource creates a data source adapter from its configuration
+func NewSource(cfg SourceConfig) (DataSource, error) {
+	switch cfg.Type {
+	case SourceTypeSimulated, "":
+		name := cfg.Name
+		if name == "" {
+			name = SourceTypeSimulated
+		}
+		return &SimulatedSource{name: name}, nil
+	case SourceTypeHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("source %s: url is required", cfg.Name)
+		}
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("source %s: path is required", cfg.Name)
+		}
+		if cfg.Name == "" {
+			cfg.Name = cfg.URL
+		}
+		if cfg.Scale == 0 {
+			cfg.Scale = 1
+		}
+		return &HTTPSource{
+			cfg:    cfg,
+			client: &http.Client{},
+		}, nil
+	default:
+		return nil, fmt.Errorf("source %s: unknown type %q", cfg.Name, cfg.Type)
+	}
+}
+
+// SimulatedSource generates values with random latency, failures and variance
+type SimulatedSource struct {
+	name string
+}
+
+// NewSimulatedSource creates the default simulated data source
+func NewSimulatedSource() *SimulatedSource {
+	return &SimulatedSource{name: SourceTypeSimulated}
+}
+
+// Name returns the source name
+func (s *SimulatedSource) Name() string {
+	return s.name
+}
+
+// Fetch simulates fetching oracle data with latency and occasional failures
+func (s *SimulatedSource) Fetch(ctx context.Context, query string) (float64, error) {
+	// Simulate random delay (100ms to 2s)
+	delay := time.Duration(rand.Intn(1900)+100) * time.Millisecond
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	// Simulate occasional failures (10% chance)
+	if rand.Float64() < 0.1 {
+		return 0, fmt.Errorf("simulated worker failure")
+	}
+
+	return simulateResponse(query), nil
+}
+
+// simulateResponse generates a random value with variance based on query
+func simulateResponse(query string) float64 {
+	// Base value varies by query type
+	var baseValue float64
+	switch query {
+	case "BTC/USD":
+		baseValue = 42000.0
+	case "ETH/USD":
+		baseValue = 2500.0
+	case "SOL/USD":
+		baseValue = 100.0
+	case "MATIC/USD":
+		baseValue = 0.8
+	default:
+		baseValue = 1000.0
+	}
+
+	// Add random variance (±5%)
+	variance := (rand.Float64() - 0.5) * 0.1 * baseValue
+	return baseValue + variance
+}
+
+// HTTPSource fetches prices from an HTTP JSON endpoint
+type HTTPSource struct {
+	cfg    SourceConfig
+	client *http.Client
+}
+
+// Name returns the source name
+func (s *HTTPSource) Name() string {
+	return s.cfg.Name
+}
+
+// Fetch requests the configured URL and extracts the price at the configured path
+func (s *HTTPSource) Fetch(ctx context.Context, query string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url(query), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %v", err)
+	}
+	for key, value := range s.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("upstream returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	value, err := extractPath(doc, s.cfg.Path)
+	if err != nil {
+		return 0, err
+	}
+	return value * s.cfg.Scale, nil
+}
+
+// url fills the URL template for query
+func (s *HTTPSource) url(query string) string {
+	symbol := query
+	if mapped, ok := s.cfg.Symbols[query]; ok {
+		symbol = mapped
+	}
+
+	base, quote, _ := strings.Cut(query, "/")
+	return strings.NewReplacer(
+		"{symbol}", symbol,
+		"{base}", base,
+		"{quote}", quote,
+	).Replace(s.cfg.URL)
+}
+
+// extractPath walks a decoded JSON document along a dot-separated path.
+// Numeric segments index into arrays. The leaf may be a number or a numeric string.
+func extractPath(doc any, path string) (float64, error) {
+	current := doc
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[segment]
+			if !ok {
+				return 0, fmt.Errorf("path %s: key %q not found", path, segment)
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return 0, fmt.Errorf("path %s: invalid array index %q", path, segment)
+			}
+			current = node[index]
+		default:
+			return 0, fmt.Errorf("path %s: cannot descend into %q", path, segment)
+		}
+	}
+
+	switch leaf := current.(type) {
+	case float64:
+		return leaf, nil
+	case string:
+		value, err := strconv.ParseFloat(leaf, 64)
+		if err != nil {
+			return 0, fmt.Errorf("path %s: %q is not a number", path, leaf)
+		}
+		return value, nil
+	default:
+		return 0, fmt.Errorf("path %s: value is not a number", path)
+	}
+}
@@ -0,0 +1,178 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExtractPath(t *testing.T) {
+	const doc = `{
+		"price": 42000.5,
+		"quoted": "2500.25",
+		"name": "bitcoin",
+		"active": true,
+		"data": [{"last": 100}, {"last": "101.5"}],
+		"result": {"XXBTZUSD": {"c": ["43000.1", "0.5"]}}
+	}`
+	var decoded any
+	if err := json.Unmarshal([]byte(doc), &decoded); err != nil {
+		t.Fatalf("failed to decode test document: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    float64
+		wantErr bool
+	}{
+		{"top-level number", "price", 42000.5, false},
+		{"numeric string", "quoted", 2500.25, false},
+		{"array index", "data.0.last", 100, false},
+		{"numeric string in array", "data.1.last", 101.5, false},
+		{"nested maps and array", "result.XXBTZUSD.c.0", 43000.1, false},
+		{"missing key", "volume", 0, true},
+		{"missing nested key", "result.XETHZUSD.c.0", 0, true},
+		{"index out of range", "data.2.last", 0, true},
+		{"negative index", "data.-1.last", 0, true},
+		{"non-numeric index", "data.first.last", 0, true},
+		{"descend into a number", "price.value", 0, true},
+		{"non-numeric string", "name", 0, true},
+		{"boolean leaf", "active", 0, true},
+		{"object leaf", "result", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractPath(decoded, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("extractPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SourceConfig
+		wantErr bool
+	}{
+		{"simulated by default", SourceConfig{}, false},
+		{"simulated", SourceConfig{Name: "sim", Type: SourceTypeSimulated}, false},
+		{"http", SourceConfig{Name: "api", Type: SourceTypeHTTP, URL: "http://localhost/{symbol}", Path: "price"}, false},
+		{"http without url", SourceConfig{Name: "api", Type: SourceTypeHTTP, Path: "price"}, true},
+		{"http without path", SourceConfig{Name: "api", Type: SourceTypeHTTP, URL: "http://localhost"}, true},
+		{"unknown type", SourceConfig{Name: "ws", Type: "websocket"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSource(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("NewSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSourceConfigServes(t *testing.T) {
+	tests := []struct {
+		queries []string
+		query   string
+		want    bool
+	}{
+		{nil, "BTC/USD", true},
+		{[]string{"*"}, "BTC/USD", true},
+		{[]string{"ETH/USD", "BTC/USD"}, "BTC/USD", true},
+		{[]string{"ETH/USD"}, "BTC/USD", false},
+	}
+
+	for _, tt := range tests {
+		if got := (SourceConfig{Queries: tt.queries}).serves(tt.query); got != tt.want {
+			t.Errorf("serves(%q) with queries %v = %v, want %v", tt.query, tt.queries, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPSourceFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/price/BTCUSDT":
+			if r.Header.Get("X-Api-Key") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"data": {"price": "4200050000000"}}`))
+		case "/pair/ETH-USD":
+			w.Write([]byte(`{"data": {"price": 2500}}`))
+		case "/text":
+			w.Write([]byte(`{"data": {"price": "n/a"}}`))
+		case "/html":
+			w.Write([]byte(`<html>maintenance</html>`))
+		case "/slow":
+			select {
+			case <-time.After(2 * time.Second):
+			case <-r.Context().Done():
+			}
+			w.Write([]byte(`{"data": {"price": 1}}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		cfg     SourceConfig
+		query   string
+		timeout time.Duration
+		want    float64
+		wantErr bool
+	}{
+		{"symbol mapping, headers and scale", SourceConfig{
+			URL:     server.URL + "/price/{symbol}",
+			Path:    "data.price",
+			Scale:   1e-8,
+			Symbols: map[string]string{"BTC/USD": "BTCUSDT"},
+			Headers: map[string]string{"X-Api-Key": "secret"},
+		}, "BTC/USD", time.Second, 42000.5, false},
+		{"base and quote", SourceConfig{URL: server.URL + "/pair/{base}-{quote}", Path: "data.price"},
+			"ETH/USD", time.Second, 2500, false},
+		{"non-2xx status", SourceConfig{URL: server.URL + "/down", Path: "data.price"}, "BTC/USD", time.Second, 0, true},
+		{"non-numeric field", SourceConfig{URL: server.URL + "/text", Path: "data.price"}, "BTC/USD", time.Second, 0, true},
+		{"not json", SourceConfig{URL: server.URL + "/html", Path: "data.price"}, "BTC/USD", time.Second, 0, true},
+		{"missing field", SourceConfig{URL: server.URL + "/pair/{base}-{quote}", Path: "data.bid"},
+			"ETH/USD", time.Second, 0, true},
+		{"timeout", SourceConfig{URL: server.URL + "/slow", Path: "data.price"}, "BTC/USD", 50 * time.Millisecond, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Type = SourceTypeHTTP
+			source, err := NewSource(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewSource() error = %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			start := time.Now()
+			got, err := source.Fetch(ctx, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > tt.timeout+500*time.Millisecond {
+				t.Errorf("Fetch() took %v, longer than its %v deadline", elapsed, tt.timeout)
+			}
+			if diff := got - tt.want; diff > 1e-6 || diff < -1e-6 {
+				t.Errorf("Fetch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"distributed-worker-system/pkg/models"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// taskAckWait is how long JetStream waits for a task acknowledgement before redelivering it
+	taskAckWait = 30 * time.Second
+	// taskMaxDeliver bounds how often a task is delivered to the same worker
+	taskMaxDeliver = 3
+	// consumerInactiveThreshold is how long a worker's consumer survives without the worker
+	consumerInactiveThreshold = time.Hour
+	// fetchWait is how long a single fetch waits for a task
+	fetchWait = 5 * time.Second
+	// streamRetryInterval is how often a worker checks whether the task stream exists yet
+	streamRetryInterval = 2 * time.Second
+)
+
+// UseTaskStream makes SubscribeTasks consume tasks from a JetStream stream through a
+// durable consumer named after the worker, instead of a core NATS subscription.
+// Tasks published while the worker is disconnected are delivered when it returns.
+// It must be called before SubscribeTasks.
+func (w *Worker) UseTaskStream(stream string) {
+	w.taskStream = stream
+}
+
+// consumerName derives the worker's durable consumer name from its ID
+func consumerName(workerID string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" \t.*>", r) {
+			return '_'
+		}
+		return r
+	}, workerID)
+}
+
+// consumeTaskStream pulls tasks from the worker's durable consumer whenever a
+// processing slot is free, until ctx is cancelled. Each task is acknowledged once
+// its result is published, so tasks in progress when the worker dies are redelivered.
+func (w *Worker) consumeTaskStream(ctx context.Context) error {
+	js, err := w.nc.JetStream()
+	if err != nil {
+		return fmt.Errorf("failed to get JetStream context: %v", err)
+	}
+
+	// The consumer is created explicitly and bound to, so it is kept when the
+	// subscription ends and the worker resumes where it left off after a restart
+	durable := consumerName(w.ID)
+	consumer := &nats.ConsumerConfig{
+		Durable:           durable,
+		Description:       fmt.Sprintf("Tasks for worker %s", w.ID),
+		DeliverPolicy:     nats.DeliverNewPolicy,
+		AckPolicy:         nats.AckExplicitPolicy,
+		AckWait:           taskAckWait,
+		MaxDeliver:        taskMaxDeliver,
+		FilterSubject:     w.subjects.Tasks,
+		InactiveThreshold: consumerInactiveThreshold,
+	}
+	for {
+		_, err := js.AddConsumer(w.taskStream, consumer)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, nats.ErrStreamNotFound) {
+			return fmt.Errorf("failed to create consumer %s on stream %s: %v", durable, w.taskStream, err)
+		}
+
+		// The coordinator creates the stream; wait for it to start
+		log.Printf("⏳ Worker %s waiting for task stream %s", w.ID, w.taskStream)
+		select {
+		case <-time.After(streamRetryInterval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	sub, err := js.PullSubscribe(w.subjects.Tasks, durable, nats.Bind(w.taskStream, durable))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to stream %s: %v", w.taskStream, err)
+	}
+	defer sub.Unsubscribe()
+
+	log.Printf("👂 Worker %s consuming %s from stream %s as %s", w.ID, w.subjects.Tasks, w.taskStream, durable)
+
+	for {
+		// Only fetch a task when there is a slot to process it; tasks wait in the stream otherwise
+		select {
+		case w.slots <- struct{}{}:
+		case <-ctx.Done():
+			log.Printf("🚰 Worker %s stopped consuming stream %s", w.ID, w.taskStream)
+			return nil
+		}
+
+		fetchCtx, cancel := context.WithTimeout(ctx, fetchWait)
+		msgs, err := sub.Fetch(1, nats.Context(fetchCtx))
+		cancel()
+		if err != nil || len(msgs) == 0 {
+			<-w.slots
+			if err != nil && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) &&
+				!errors.Is(err, nats.ErrTimeout) {
+				log.Printf("⚠️  Worker %s failed to fetch tasks: %v", w.ID, err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		w.handleStreamTask(msgs[0])
+	}
+}
+
+// handleStreamTask processes a task delivered from the stream in the slot already
+// claimed for it. Tasks whose collection window has passed are acknowledged and skipped.
+func (w *Worker) handleStreamTask(msg *nats.Msg) {
+	var req models.OracleRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		<-w.slots
+		log.Printf("❌ Worker %s failed to unmarshal task: %v", w.ID, err)
+		msg.Term()
+		return
+	}
+
+	if meta, err := msg.Metadata(); err == nil && req.Options.TimeoutMs > 0 &&
+		time.Since(meta.Timestamp) > req.Options.Timeout() {
+		<-w.slots
+		log.Printf("⌛ Worker %s skipping stale task %s published %v ago", w.ID, req.ID, time.Since(meta.Timestamp).Round(time.Millisecond))
+		msg.Ack()
+		return
+	}
+
+	go w.runTask(req, func() {
+		if err := msg.Ack(); err != nil {
+			log.Printf("⚠️  Worker %s failed to acknowledge task %s: %v", w.ID, req.ID, err)
+		}
+	})
+}
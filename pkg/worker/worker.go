@@ -2,74 +2,341 @@ package worker
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"math/rand"
+	"os"
+	"strings"
 	"time"
 
 	"distributed-worker-system/pkg/models"
+	"distributed-worker-system/pkg/signing"
 	"distributed-worker-system/pkg/utils"
 
 	"github.com/nats-io/nats.go"
 )
 
+const (
+	// DefaultHeartbeatInterval is how often workers announce themselves to the coordinator
+	DefaultHeartbeatInterval = 5 * time.Second
+	// DefaultMaxInFlight is how many tasks a worker processes concurrently by default
+	DefaultMaxInFlight = 16
+	// DefaultGracePeriod is how long a stopping worker waits for in-flight tasks
+	DefaultGracePeriod = 10 * time.Second
+	// drainPollInterval is how often SubscribeTasks checks whether its subscription has drained
+	drainPollInterval = 50 * time.Millisecond
+)
+
+// ErrDuplicateID is returned by Register when another live process holds the worker ID
+var ErrDuplicateID = errors.New("worker id is in use by another live process")
+
 // Worker represents a worker that processes oracle tasks via NATS
 type Worker struct {
-	ID   string
-	Port int
-	nc   *nats.Conn
+	ID         string
+	InstanceID string
+	Port       int
+	Endpoint   string
+	nc         *nats.Conn
+	sources    []configuredSource
+	key        ed25519.PrivateKey
+	subjects   models.Subjects
+	// slots bounds the number of tasks processed concurrently
+	slots chan struct{}
+	// taskStream is the JetStream stream tasks are consumed from; empty means core NATS
+	taskStream string
+}
+
+// configuredSource pairs a data source with the configuration that selects it
+type configuredSource struct {
+	cfg    SourceConfig
+	source DataSource
 }
 
 // NewWorker creates a new worker instance
 func NewWorker(port int) *Worker {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+
 	return &Worker{
-		ID:   utils.GenerateWorkerID(),
-		Port: port,
+		ID:         utils.GenerateWorkerID(),
+		InstanceID: utils.GenerateInstanceID(),
+		subjects:   models.DefaultSubjects(),
+		slots:      make(chan struct{}, DefaultMaxInFlight),
+		Port:       port,
+		Endpoint:   fmt.Sprintf("%s:%d", hostname, port),
+		sources: []configuredSource{{
+			cfg:    SourceConfig{Type: SourceTypeSimulated, TimeoutMs: 5000},
+			source: NewSimulatedSource(),
+		}},
+	}
+}
+
+// SetKey sets the ed25519 key the worker signs its results with and
+// derives the worker ID from its public key
+func (w *Worker) SetKey(key ed25519.PrivateKey) {
+	w.key = key
+	w.ID = signing.WorkerID(key.Public().(ed25519.PublicKey))
+}
+
+// SetMaxInFlight sets how many tasks the worker processes concurrently.
+// Tasks arriving while all slots are busy are answered with a saturated result.
+// It must be called before SubscribeTasks.
+func (w *Worker) SetMaxInFlight(n int) error {
+	if n < 1 {
+		return fmt.Errorf("max in-flight tasks must be at least 1, got %d", n)
+	}
+	w.slots = make(chan struct{}, n)
+	return nil
+}
+
+// SetSubjects sets the NATS subjects the worker publishes and subscribes on
+func (w *Worker) SetSubjects(subjects models.Subjects) {
+	w.subjects = subjects
+}
+
+// SetID overrides the worker ID
+func (w *Worker) SetID(id string) {
+	w.ID = id
+}
+
+// publicKey returns the worker's public key, or nil if it has no key
+func (w *Worker) publicKey() []byte {
+	if w.key == nil {
+		return nil
+	}
+	return w.key.Public().(ed25519.PublicKey)
+}
+
+// Register announces the worker to the coordinator and waits for acknowledgement
+func (w *Worker) Register(nc *nats.Conn) error {
+	w.nc = nc
+
+	req := models.RegisterRequest{
+		ID:          w.ID,
+		InstanceID:  w.InstanceID,
+		Endpoint:    w.Endpoint,
+		PublicKey:   w.publicKey(),
+		MaxInFlight: cap(w.slots),
+		Timestamp:   time.Now().UTC(),
+	}
+	if w.key != nil {
+		signing.SignRegister(w.key, &req)
 	}
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal registration: %v", err)
+	}
+
+	msg, err := nc.Request(w.subjects.Register, reqBytes, 2*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to register with coordinator: %v", err)
+	}
+
+	var resp models.RegisterResponse
+	if err := json.Unmarshal(msg.Data, &resp); err != nil {
+		return fmt.Errorf("failed to decode registration response: %v", err)
+	}
+	if resp.Reason == models.RegisterReasonDuplicate {
+		return fmt.Errorf("%w: %s", ErrDuplicateID, resp.Message)
+	}
+	if resp.Status != "registered" {
+		return fmt.Errorf("registration rejected: %s", resp.Message)
+	}
+
+	log.Printf("✅ Worker %s registered: %s", w.ID, resp.Message)
+	return nil
+}
+
+// StartHeartbeat publishes heartbeats at the given interval until ctx is cancelled
+func (w *Worker) StartHeartbeat(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.publishHeartbeat(); err != nil {
+					log.Printf("❌ Worker %s failed to publish heartbeat: %v", w.ID, err)
+				}
+			}
+		}
+	}()
 }
 
-// SubscribeTasks listens for new tasks and processes them
-func (w *Worker) SubscribeTasks(nc *nats.Conn) error {
+// publishHeartbeat publishes a single heartbeat to the coordinator
+func (w *Worker) publishHeartbeat() error {
+	hb := models.Heartbeat{
+		ID:          w.ID,
+		InstanceID:  w.InstanceID,
+		Endpoint:    w.Endpoint,
+		PublicKey:   w.publicKey(),
+		InFlight:    len(w.slots),
+		MaxInFlight: cap(w.slots),
+		Timestamp:   time.Now().UTC(),
+	}
+	if w.key != nil {
+		signing.SignHeartbeat(w.key, &hb)
+	}
+	hbBytes, err := json.Marshal(hb)
+	if err != nil {
+		return fmt.Errorf("failed to marshal heartbeat: %v", err)
+	}
+
+	return w.nc.Publish(w.subjects.Heartbeat, hbBytes)
+}
+
+// SubscribeTasks listens for new tasks and processes them until ctx is cancelled.
+// It then drains the subscription, so tasks already delivered are still picked
+// up, and returns once no further tasks will arrive. With a task stream set by
+// UseTaskStream, tasks are pulled from JetStream instead.
+func (w *Worker) SubscribeTasks(ctx context.Context, nc *nats.Conn) error {
 	w.nc = nc
+	if w.taskStream != "" {
+		return w.consumeTaskStream(ctx)
+	}
 
 	// Subscribe to oracle.tasks subject
-	sub, err := nc.Subscribe("oracle.tasks", func(msg *nats.Msg) {
+	sub, err := nc.Subscribe(w.subjects.Tasks, func(msg *nats.Msg) {
 		var req models.OracleRequest
 		if err := json.Unmarshal(msg.Data, &req); err != nil {
 			log.Printf("❌ Worker %s failed to unmarshal task: %v", w.ID, err)
 			return
 		}
 
-		log.Printf("📋 Worker %s processing task %s: %s", w.ID, req.ID, req.Query)
-
-		// Process the task
-		result := w.processTask(req)
-
-		// Publish result back to oracle.results
-		if err := w.publishResult(result); err != nil {
-			log.Printf("❌ Worker %s failed to publish result: %v", w.ID, err)
+		// Claim a processing slot without blocking the subscription callback.
+		// When every slot is busy, tell the coordinator instead of queueing.
+		select {
+		case w.slots <- struct{}{}:
+		default:
+			log.Printf("🚦 Worker %s saturated (%d in flight), skipping task %s", w.ID, cap(w.slots), req.ID)
+			if err := w.publishResult(req, w.saturatedResult(req)); err != nil {
+				log.Printf("❌ Worker %s failed to publish result: %v", w.ID, err)
+			}
+			return
 		}
+
+		go w.runTask(req, func() {})
 	})
 	if err != nil {
-		return fmt.Errorf("failed to subscribe to oracle.tasks: %v", err)
+		return fmt.Errorf("failed to subscribe to %s: %v", w.subjects.Tasks, err)
 	}
 
-	log.Printf("👂 Worker %s subscribed to oracle.tasks", w.ID)
+	log.Printf("👂 Worker %s subscribed to %s", w.ID, w.subjects.Tasks)
 
 	// Keep subscription alive
-	<-context.Background().Done()
-	return sub.Unsubscribe()
+	<-ctx.Done()
+
+	log.Printf("🚰 Worker %s draining %s subscription", w.ID, w.subjects.Tasks)
+	if err := sub.Drain(); err != nil {
+		return fmt.Errorf("failed to drain %s subscription: %v", w.subjects.Tasks, err)
+	}
+	for sub.IsValid() {
+		time.Sleep(drainPollInterval)
+	}
+	return nil
+}
+
+// runTask processes a task in a claimed slot, publishes its result and releases
+// the slot. done is called once the result has been published.
+func (w *Worker) runTask(req models.OracleRequest, done func()) {
+	defer func() { <-w.slots }()
+
+	log.Printf("📋 Worker %s processing task %s: %s", w.ID, req.ID, req.Query)
+
+	// Process the task
+	result := w.processTask(req)
+
+	// Publish result back to the coordinator
+	if err := w.publishResult(req, result); err != nil {
+		log.Printf("❌ Worker %s failed to publish result: %v", w.ID, err)
+		return
+	}
+	done()
 }
 
-// publishResult publishes worker result to oracle.results subject
-func (w *Worker) publishResult(result models.WorkerResult) error {
+// Shutdown waits up to grace for in-flight tasks to finish, deregisters the
+// worker from the coordinator and closes the NATS connection
+func (w *Worker) Shutdown(grace time.Duration) error {
+	if w.nc == nil {
+		return nil
+	}
+
+	if inFlight := w.waitInFlight(grace); inFlight > 0 {
+		log.Printf("⚠️  Worker %s abandoning %d in-flight tasks after %v", w.ID, inFlight, grace)
+	}
+
+	if err := w.publishDeregistration(); err != nil {
+		log.Printf("❌ Worker %s failed to deregister: %v", w.ID, err)
+	}
+
+	// Flush pending results and the deregistration before closing
+	if err := w.nc.FlushTimeout(2 * time.Second); err != nil {
+		log.Printf("⚠️  Worker %s failed to flush NATS connection: %v", w.ID, err)
+	}
+	return w.Close()
+}
+
+// waitInFlight claims every processing slot, waiting up to grace for running
+// tasks to release theirs. It returns the number of tasks still running.
+func (w *Worker) waitInFlight(grace time.Duration) int {
+	deadline := time.NewTimer(grace)
+	defer deadline.Stop()
+
+	for claimed := 0; claimed < cap(w.slots); claimed++ {
+		select {
+		case w.slots <- struct{}{}:
+		case <-deadline.C:
+			return cap(w.slots) - claimed
+		}
+	}
+	return 0
+}
+
+// publishDeregistration tells the coordinator the worker is going away
+func (w *Worker) publishDeregistration() error {
+	dereg := models.Deregistration{
+		ID:         w.ID,
+		InstanceID: w.InstanceID,
+		Timestamp:  time.Now().UTC(),
+	}
+	if w.key != nil {
+		signing.SignDeregister(w.key, &dereg)
+	}
+	deregBytes, err := json.Marshal(dereg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deregistration: %v", err)
+	}
+
+	return w.nc.Publish(w.subjects.Deregister, deregBytes)
+}
+
+// publishResult publishes a worker result to the task's reply subject, falling back
+// to the shared results subject. Only reply subjects under the results subject are
+// honoured, so a task cannot make workers publish onto unrelated subjects.
+func (w *Worker) publishResult(req models.OracleRequest, result models.WorkerResult) error {
+	result.InstanceID = w.InstanceID
+	result.Query = req.Query
+	result.Timestamp = time.Now().UTC()
+	if w.key != nil {
+		signing.SignResult(w.key, &result)
+	}
+
 	resultBytes, err := json.Marshal(result)
 	if err != nil {
 		return fmt.Errorf("failed to marshal result: %v", err)
 	}
 
-	if err := w.nc.Publish("oracle.results", resultBytes); err != nil {
+	subject := w.subjects.Results
+	if strings.HasPrefix(req.ReplyTo, w.subjects.Results+".") {
+		subject = req.ReplyTo
+	}
+	if err := w.nc.Publish(subject, resultBytes); err != nil {
 		return fmt.Errorf("failed to publish result: %v", err)
 	}
 
@@ -77,57 +344,80 @@ func (w *Worker) publishResult(result models.WorkerResult) error {
 	return nil
 }
 
-// processTask simulates fetching oracle data with latency/failure
+// saturatedResult builds the result reported for a task skipped at capacity
+func (w *Worker) saturatedResult(req models.OracleRequest) models.WorkerResult {
+	return models.WorkerResult{
+		WorkerID:  w.ID,
+		RequestID: req.ID,
+		Err:       fmt.Sprintf("worker saturated: %d tasks in flight", cap(w.slots)),
+		Saturated: true,
+	}
+}
+
+// processTask fetches the value for a task from the data source serving its query
 func (w *Worker) processTask(req models.OracleRequest) models.WorkerResult {
 	startTime := time.Now()
 
-	// Simulate random delay (100ms to 2s)
-	delay := time.Duration(rand.Intn(1900)+100) * time.Millisecond
-	time.Sleep(delay)
-
-	// Simulate occasional failures (10% chance)
-	if rand.Float64() < 0.1 {
+	source, ok := w.sourceFor(req.Query)
+	if !ok {
 		return models.WorkerResult{
 			WorkerID:     w.ID,
 			RequestID:    req.ID,
 			Value:        0,
-			Err:          "simulated worker failure",
+			Err:          fmt.Sprintf("no data source configured for %s", req.Query),
 			ResponseTime: time.Since(startTime),
 		}
 	}
 
-	// Simulate response with slight variance
-	value := w.simulateResponse(req.Query)
+	ctx, cancel := context.WithTimeout(context.Background(), source.cfg.Timeout())
+	defer cancel()
+
+	value, err := source.source.Fetch(ctx, req.Query)
+	if err != nil {
+		return models.WorkerResult{
+			WorkerID:     w.ID,
+			RequestID:    req.ID,
+			Value:        0,
+			Err:          err.Error(),
+			Source:       source.source.Name(),
+			ResponseTime: time.Since(startTime),
+		}
+	}
 
 	return models.WorkerResult{
 		WorkerID:     w.ID,
 		RequestID:    req.ID,
 		Value:        value,
 		Err:          "",
+		Source:       source.source.Name(),
 		ResponseTime: time.Since(startTime),
 	}
 }
 
-// simulateResponse generates a random value with variance based on query
-func (w *Worker) simulateResponse(query string) float64 {
-	// Base value varies by query type
-	var baseValue float64
-	switch query {
-	case "BTC/USD":
-		baseValue = 42000.0
-	case "ETH/USD":
-		baseValue = 2500.0
-	case "SOL/USD":
-		baseValue = 100.0
-	case "MATIC/USD":
-		baseValue = 0.8
-	default:
-		baseValue = 1000.0
+// SetSources replaces the worker's data sources. Sources are tried in order and
+// the first one serving a query answers it.
+func (w *Worker) SetSources(configs []SourceConfig) error {
+	sources := make([]configuredSource, 0, len(configs))
+	for _, cfg := range configs {
+		source, err := NewSource(cfg)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, configuredSource{cfg: cfg, source: source})
 	}
 
-	// Add random variance (±5%)
-	variance := (rand.Float64() - 0.5) * 0.1 * baseValue
-	return baseValue + variance
+	w.sources = sources
+	return nil
+}
+
+// sourceFor returns the first data source that serves query
+func (w *Worker) sourceFor(query string) (configuredSource, bool) {
+	for _, source := range w.sources {
+		if source.cfg.serves(query) {
+			return source, true
+		}
+	}
+	return configuredSource{}, false
 }
 
 // GetID returns the worker's ID